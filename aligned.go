@@ -0,0 +1,123 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"context"
+	"time"
+)
+
+// AlignedTicker holds a channel that delivers "ticks" of a clock at regular
+// boundaries of the wall clock (e.g. at the top of every minute or on each
+// 5 minute mark). As with NormalTicker, each tick is sent on either C or,
+// along with its metadata, on T.
+type AlignedTicker struct {
+	C chan time.Time
+	T chan Tick
+	ticker
+	period time.Duration
+	offset time.Duration
+	loc    *time.Location
+}
+
+// NewAlignedTicker returns a new AlignedTicker containing a channel that will
+// send the current time on the channel each time the wall clock in loc
+// reaches a multiple of period, plus offset. For example, a period of
+// 5*time.Minute with an offset of 30*time.Second ticks at 00:00:30, 00:05:30,
+// 00:10:30, etc. An offset outside the range [0, period) is reduced modulo
+// period. If loc is nil, time.Local is used.
+//
+// Boundaries for periods of less than an hour are measured against the local
+// zone's offset from UTC and continue evenly across a daylight saving time
+// transition. Boundaries for periods of an hour or more follow the wall clock
+// instead: a 24h period with a 2h offset ticks at 02:00 every day, regardless
+// of whether that day is 23, 24 or 25 hours long. A boundary that falls into
+// the gap when the clock springs forward ticks at the moment of the
+// transition, and wall clock times repeated when the clock falls back only
+// tick once.
+//
+// The next boundary is recomputed from the clock after each tick, and the
+// clock is rechecked periodically while waiting, so the ticker stays aligned
+// even if the host is suspended or its clock is stepped.
+//
+// By default, the ticker will drop ticks to make up for slow receivers (see
+// WithOverrunPolicy) and will continue to send values to its channel until
+// the Stop method is called or the given context is expired.
+// NewAlignedTicker panics if period is not positive.
+func NewAlignedTicker(ctx context.Context, period, offset time.Duration, loc *time.Location, opts ...TickerOption) *AlignedTicker {
+	if period <= 0 {
+		panic("timetool: non-positive period for NewAlignedTicker")
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	if offset %= period; offset < 0 {
+		offset += period
+	}
+
+	at := &AlignedTicker{
+		C:      make(chan time.Time),
+		T:      make(chan Tick),
+		period: period,
+		offset: offset,
+		loc:    loc,
+	}
+
+	at.ticker = newTicker(at.C, at.T, at.next, wallRecheck, opts)
+
+	go at.run(ctx)
+
+	return at
+}
+
+func (at *AlignedTicker) next(t time.Time) time.Time {
+	return nextAligned(t, at.period, at.offset, at.loc)
+}
+
+// nextAligned returns the first boundary strictly after t, as described
+// for NewAlignedTicker. The offset must already be reduced to [0, period).
+func nextAligned(t time.Time, period, offset time.Duration, loc *time.Location) time.Time {
+	// Note that In also strips any monotonic clock reading.
+	t = t.In(loc)
+
+	if period < time.Hour {
+		_, zo := t.Zone()
+		z := time.Duration(zo) * time.Second
+		return t.Add(z - offset).Truncate(period).Add(period + offset - z)
+	}
+
+	// Perform the calculation with the wall clock reading of t as if it were
+	// UTC (which has no transitions) and then map the result back into loc.
+	w := wallUTC(t)
+
+	for n := w.Add(-offset).Truncate(period).Add(offset); ; {
+		n = n.Add(period)
+
+		nt := fromWallUTC(n, loc)
+		if !wallUTC(nt).Equal(n) {
+			// n was skipped when the clock sprung forward; tick at the
+			// transition.
+			if _, end := nt.ZoneBounds(); !end.IsZero() {
+				nt = end
+			}
+		}
+
+		if nt.After(t) {
+			return nt
+		}
+	}
+}
+
+func wallUTC(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	h, mi, s := t.Clock()
+	return time.Date(y, mo, d, h, mi, s, t.Nanosecond(), time.UTC)
+}
+
+func fromWallUTC(t time.Time, loc *time.Location) time.Time {
+	y, mo, d := t.Date()
+	h, mi, s := t.Clock()
+	return time.Date(y, mo, d, h, mi, s, t.Nanosecond(), loc)
+}
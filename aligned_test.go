@@ -0,0 +1,70 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNextAligned(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	ktm := mustLoadLocation(t, "Asia/Kathmandu")
+
+	cases := []struct {
+		name   string
+		from   time.Time
+		period time.Duration
+		offset time.Duration
+		want   time.Time
+	}{
+		{"minute", time.Date(2024, 5, 1, 10, 7, 12, 0, time.UTC), time.Minute, 0, time.Date(2024, 5, 1, 10, 8, 0, 0, time.UTC)},
+		{"on-boundary", time.Date(2024, 5, 1, 10, 8, 0, 0, time.UTC), time.Minute, 0, time.Date(2024, 5, 1, 10, 9, 0, 0, time.UTC)},
+		{"offset", time.Date(2024, 5, 1, 10, 7, 12, 0, time.UTC), 5 * time.Minute, 30 * time.Second, time.Date(2024, 5, 1, 10, 10, 30, 0, time.UTC)},
+		{"odd-zone", time.Date(2024, 5, 1, 10, 7, 0, 0, ktm), 15 * time.Minute, 0, time.Date(2024, 5, 1, 10, 15, 0, 0, ktm)},
+		{"odd-zone-hourly", time.Date(2024, 5, 1, 10, 7, 0, 0, ktm), time.Hour, 0, time.Date(2024, 5, 1, 11, 0, 0, 0, ktm)},
+		{"daily", time.Date(2024, 5, 1, 10, 7, 0, 0, nyc), 24 * time.Hour, 2 * time.Hour, time.Date(2024, 5, 2, 2, 0, 0, 0, nyc)},
+		{"daily-short-day", time.Date(2024, 3, 9, 4, 0, 0, 0, nyc), 24 * time.Hour, 3 * time.Hour, time.Date(2024, 3, 10, 3, 0, 0, 0, nyc)},
+		{"daily-long-day", time.Date(2024, 11, 2, 3, 0, 0, 0, nyc), 24 * time.Hour, 2 * time.Hour, time.Date(2024, 11, 3, 2, 0, 0, 0, nyc)},
+		{"daily-in-gap", time.Date(2024, 3, 10, 1, 45, 0, 0, nyc), 24 * time.Hour, 150 * time.Minute, time.Date(2024, 3, 10, 3, 0, 0, 0, nyc)},
+		{"hourly-fall-back", time.Date(2024, 11, 3, 1, 0, 0, 0, nyc), time.Hour, 0, time.Date(2024, 11, 3, 2, 0, 0, 0, nyc)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := nextAligned(tc.from, tc.period, tc.offset, tc.from.Location()); !got.Equal(tc.want) {
+				t.Errorf("nextAligned(%v, %v, %v) == %v; Wanted %v", tc.from, tc.period, tc.offset, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAlignedTicker(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 3*int(time.Millisecond), time.UTC)
+	fc := newFakeClock(t, start)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	period := 50 * time.Millisecond
+	at := NewAlignedTicker(ctx, period, 0, time.UTC)
+	defer at.Stop()
+
+	for i := 1; i <= 5; i++ {
+		fc.Fire(t)
+
+		if tv, want := <-at.C, start.Truncate(period).Add(time.Duration(i)*period); !tv.Equal(want) {
+			t.Errorf("tick %d at %v; Wanted %v", i, tv, want)
+		}
+	}
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("cannot load location %q: %v", name, err)
+	}
+	return loc
+}
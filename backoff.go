@@ -22,6 +22,8 @@ package timetool
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/rand"
 	"time"
 )
@@ -38,8 +40,29 @@ const (
 // (zero based) iteration number.
 type RetryFunc func(i int) bool
 
-// Backoff defines the parameters for a set of retries with exponential
-// backoff.
+// RetryErrFunc is like RetryFunc except that it indicates success by returning
+// a nil error. The last error returned is reported by the *RetryError returned
+// when all attempts have failed. The function is passed the Context given to
+// RetryErr along with the current (zero based) iteration number.
+type RetryErrFunc func(ctx context.Context, i int) error
+
+// BackoffCurve selects how the delays between a Backoff's attempts grow.
+type BackoffCurve int
+
+const (
+	// ExponentialCurve doubles the delay before each successive attempt.
+	// This is the default.
+	ExponentialCurve BackoffCurve = iota
+
+	// CubicCurve schedules each attempt relative to the start of the first,
+	// such that attempt i begins once Coefficient * i³ has elapsed. This is
+	// the schedule used by the deprecated RetryWithBackoff function, where
+	// attempt i (of n) begins at fraction i³/n³ of the time allowed.
+	CubicCurve
+)
+
+// Backoff defines the parameters for a set of retries with exponential (or,
+// optionally, cubic) backoff.
 type Backoff struct {
 	// Iterations declares the maximum number execution attempts.
 	Iterations int
@@ -50,8 +73,14 @@ type Backoff struct {
 	// Jitter is a random modifier percentage applied to each delay period.
 	Jitter float64
 
-	startWait time.Duration
-	initWait  time.Duration
+	// Curve determines how delays grow from one attempt to the next. The
+	// zero value is ExponentialCurve.
+	Curve BackoffCurve
+
+	startWait      time.Duration
+	initWait       time.Duration
+	attemptTimeout time.Duration
+	budget         *RetryBudget
 }
 
 // StdBackoff provides a Backoff with common parameters.
@@ -74,6 +103,19 @@ func CalculateBackoff(iters int, total time.Duration, jitter float64) (*Backoff,
 	return b.WithTotalDelay(total)
 }
 
+// BackoffForContext returns a new Backoff with the given number of iterations
+// and jitter, having a Coefficient value calculated to fit its retries within
+// the deadline of ctx. It is equivalent to calling FitDeadline on a Backoff
+// with the given Iterations and Jitter fields.
+func BackoffForContext(ctx context.Context, iters int, jitter float64) (*Backoff, error) {
+	b := Backoff{
+		Iterations: iters,
+		Jitter:     jitter,
+	}
+
+	return b.FitDeadline(ctx)
+}
+
 // MustCalculateBackoff is a wrapper around CalculateBackoff that will panic if
 // an error is returned.
 func MustCalculateBackoff(iters int, total time.Duration, jitter float64) *Backoff {
@@ -95,39 +137,89 @@ func MustCalculateBackoff(iters int, total time.Duration, jitter float64) *Backo
 // A Coefficient value will only be calculated if the receiver's Iterations
 // field is greater than 2 -- since a call to Retry with only 2 iterations
 // never refers to the Coefficient field. However, if Iterations is exactly
-// 2, this method has the same effect as calling WithInitialWait.
+// 2, this method has the same effect as calling WithInitialWait. (This does
+// not apply to a receiver using CubicCurve, which always refers to its
+// Coefficient.)
 //
 // On error, a nil pointer will always be returned.
 //
-// ErrNegativeDelay is retured if the resultant total delay time, after any
-// "startup wait time" and/or "initial wait time" values are considered, is
-// calculated to be a negative value.
+// If the resultant total delay time, after any "startup wait time" and/or
+// "initial wait time" values are considered, is calculated to be a negative
+// value, the error wraps ErrNegativeDelay (and reports d as given).
 //
-// If the receiver's Iterations field is less than 2, ErrTooFewIterations is
-// returned.
+// If the receiver's Iterations field is less than 2, the error wraps
+// ErrTooFewIterations.
+//
+// These errors, along with any problems with the receiver's other fields,
+// are reported together as described for the Validate method. Since they are
+// wrapped, test for them using errors.Is rather than ==.
 func (b Backoff) WithTotalDelay(d time.Duration) (*Backoff, error) {
-	if b.Iterations < minIterations {
-		return nil, ErrTooFewIterations
-	}
-
-	if b.Iterations == minIterations {
+	if b.Iterations < minIterations || (b.Iterations == minIterations && b.Curve != CubicCurve) {
 		b.Coefficient = 1 // To prevent future validation failure
-		return b.WithInitialWait(d), nil
+		out := b.WithInitialWait(d)
+		if err := out.Validate(); err != nil {
+			return nil, err
+		}
+		return out, nil
 	}
 
-	if d -= (b.startWait + b.initWait); d < 0 {
-		return nil, ErrNegativeDelay
+	delay := d - (b.startWait + b.initWait)
+	if delay < 0 {
+		b.Coefficient = 1 // So as not to report a problem that isn't there
+		return nil, errors.Join(&ValidationError{Field: "total delay", Value: d, Err: ErrNegativeDelay}, b.Validate())
 	}
 
-	b.Coefficient = time.Duration(float64(d) / float64(int((1<<(b.Iterations-2))-1)))
+	switch b.Curve {
+	case CubicCurve:
+		n := b.Iterations - 1
+		b.Coefficient = time.Duration(float64(delay) / float64(n*n*n))
 
-	if err := b.validate(); err != nil {
+	default:
+		b.Coefficient = time.Duration(float64(delay) / float64(int((1<<(b.Iterations-2))-1)))
+	}
+
+	if err := b.Validate(); err != nil {
 		return nil, err
 	}
 
 	return &b, nil
 }
 
+// FitDeadline returns a pointer to its receiver with a new Coefficient value
+// calculated, as with WithTotalDelay, to spread its retries across the time
+// remaining before the deadline of ctx.
+//
+// The final 1/Iterations of the remaining time is held back so that the
+// final attempt has time to run before the deadline. The delays are also
+// scaled down to allow for the largest increase that the receiver's Jitter
+// could apply to them. Time spent in the attempts themselves is otherwise
+// not accounted for.
+//
+// If ctx has no deadline, ErrMissingDeadline is returned. If its deadline
+// has already passed, ctx.Err() is returned -- or, if ctx is somehow not yet
+// done, ErrTimeWarp. Otherwise, errors are as described for WithTotalDelay.
+func (b Backoff) FitDeadline(ctx context.Context) (*Backoff, error) {
+	dl, ok := ctx.Deadline()
+	if !ok {
+		return nil, ErrMissingDeadline
+	}
+
+	remaining := dl.Sub(timeNow())
+	if remaining <= 0 {
+		return nil, contextDoneOr(ctx, ErrTimeWarp)
+	}
+
+	if b.Iterations > 0 {
+		remaining -= remaining / time.Duration(b.Iterations)
+	}
+
+	if b.Jitter > 0 {
+		remaining = time.Duration(float64(remaining) / (1 + b.Jitter/200))
+	}
+
+	return b.WithTotalDelay(remaining)
+}
+
 // MustTotalDelay is a wrapper around WithTotalDelay that will panic if an
 // error is returned.
 func (b Backoff) MustTotalDelay(d time.Duration) *Backoff {
@@ -174,6 +266,32 @@ func (b Backoff) WithInitialWait(d time.Duration) *Backoff {
 	return &b
 }
 
+// WithAttemptTimeout returns a pointer to its receiver that limits each
+// attempt made by RetryErr to the given duration. Once an attempt's time is
+// up, the Context passed to its RetryErrFunc is cancelled with a cause of
+// ErrAttemptTimeout. (Since Go 1.20 offers no way to attach a cause to a
+// deadline, this Context reports no deadline and its Err method returns
+// context.Canceled.)
+//
+// A zero value, the default, places no limit on each attempt.
+func (b Backoff) WithAttemptTimeout(d time.Duration) *Backoff {
+	b.attemptTimeout = d
+	return &b
+}
+
+// WithBudget returns a pointer to its receiver that draws its retries from
+// the given RetryBudget. Each call to Retry or RetryErr records its first
+// attempt with rb and, before each subsequent attempt, consults rb to see
+// whether another retry is allowed. If not, the call fails immediately with
+// an error matching ErrRetryBudgetExceeded (which, for RetryErr, also wraps
+// the error from the last attempt).
+//
+// A nil RetryBudget, the default, allows every retry.
+func (b Backoff) WithBudget(rb *RetryBudget) *Backoff {
+	b.budget = rb
+	return &b
+}
+
 // Retry calls the given RetryFunc up to b.Iterations times until it returns
 // true or the provided Context is cancelled, whichever comes first.
 //
@@ -194,6 +312,16 @@ func (b Backoff) WithInitialWait(d time.Duration) *Backoff {
 //
 // ...or, rather... plus or minus jitter-percent over two.
 //
+// If the receiver's Curve field is CubicCurve, each attempt (after the
+// first) is instead scheduled relative to the start of the first attempt,
+// such that:
+//
+//	offset    =  b.Coefficient * attempt_num**3 ± random_jitter
+//
+// ...and any "initial wait time" is added to each offset. Since these
+// offsets are fixed, time spent in earlier attempts does not delay later
+// ones.
+//
 // If the receiver declares fewer than 2 iterations an error will be returned.
 //
 // The receiver's delay Coefficient must be a positive, non-zero value or
@@ -201,74 +329,181 @@ func (b Backoff) WithInitialWait(d time.Duration) *Backoff {
 //
 // If b.Jitter is 0, no Jitter will be applied. Otherwise, the Jitter value
 // must be in the range (0,100) or an error will be returned.
+//
+// These errors are reported as described for the Validate method. If all
+// attempts fail, a *RetryError is returned; both match the package's
+// existing error values when tested with errors.Is.
 func (b *Backoff) Retry(ctx context.Context, retry RetryFunc) error {
-	if err := b.validate(); err != nil {
+	return b.retry(ctx, func(_ context.Context, i int) (bool, error) {
+		return retry(i), nil
+	})
+}
+
+// RetryErr is like Retry except it accepts a RetryErrFunc. If all attempts
+// fail, the returned *RetryError holds the error from the final attempt in
+// its Last field.
+//
+// Each attempt is passed its own Context, derived from ctx, which is
+// cancelled once the attempt returns. Its cause (see context.Cause) is
+// ErrAttemptFailed if the attempt failed but more attempts remain (which a
+// RetryBudget may yet deny), or ErrRetriesExhausted if it was the final
+// attempt. An attempt may also be cancelled early by a timeout (see
+// WithAttemptTimeout).
+func (b *Backoff) RetryErr(ctx context.Context, fn RetryErrFunc) error {
+	return b.retry(ctx, func(ctx context.Context, i int) (bool, error) {
+		err := fn(ctx, i)
+		return err == nil, err
+	})
+}
+
+func (b *Backoff) retry(ctx context.Context, attempt func(context.Context, int) (bool, error)) error {
+	if err := b.Validate(); err != nil {
 		return err
 	}
 
+	start := timeNow()
+
 	// We'll give attempt #0 special handling with an optional Startup Delay...
 	if err := Sleep(ctx, b.startWait); err != nil {
 		return err
 	}
 
 	// ...before running the 'retry' func for the first time...
-	if retry(0) {
+	if b.budget != nil {
+		b.budget.RecordRequest()
+	}
+
+	first := timeNow()
+	ok, last := b.attempt(ctx, 0, attempt)
+	if ok {
 		return contextDoneOr(ctx, nil)
 	}
 
 	// ...before entering our retry loop on attempt #1.
-	for attempt := 1; attempt < b.Iterations; attempt++ {
-		if attempt == 1 {
-			if err := Sleep(ctx, b.initWait); err != nil {
-				return err
+	for i := 1; i < b.Iterations; i++ {
+		if b.budget != nil && !b.budget.AllowRetry() {
+			if last != nil {
+				return fmt.Errorf("%w: %w", ErrRetryBudgetExceeded, last)
 			}
+			return ErrRetryBudgetExceeded
+		}
+
+		if err := b.wait(ctx, i, first); err != nil {
+			return err
 		}
 
-		if retry(attempt) {
+		if ok, last = b.attempt(ctx, i, attempt); ok {
 			return contextDoneOr(ctx, nil)
 		}
+	}
 
-		multiple := float64(uint(1) << (uint(attempt) - 1))
+	return contextDoneOr(ctx, &RetryError{
+		Attempts: b.Iterations,
+		Elapsed:  timeNow().Sub(start),
+		Last:     last,
+	})
+}
 
-		if b.Jitter != 0 {
-			j := (((b.Jitter * rand.Float64()) - (b.Jitter / 2)) / 100)
-			multiple += multiple * j
-		}
+// wait sleeps until attempt number i (which must be greater than zero) is
+// due. The first attempt began at the given time.
+func (b *Backoff) wait(ctx context.Context, i int, first time.Time) error {
+	if b.Curve == CubicCurve {
+		offset := b.jitter(b.Coefficient * time.Duration(i*i*i))
+		return SleepUntil(ctx, first.Add(b.initWait+offset))
+	}
 
-		if err := Sleep(ctx, b.Coefficient*time.Duration(multiple)); err != nil {
-			return err
-		}
+	if i == 1 {
+		return Sleep(ctx, b.initWait)
 	}
 
-	return contextDoneOr(ctx, ErrRetriesExhausted)
+	return Sleep(ctx, b.jitter(b.Coefficient<<(i-2)))
 }
 
-func (b *Backoff) validate() error {
+// jitter returns d adjusted by a random amount, as described for Retry.
+func (b *Backoff) jitter(d time.Duration) time.Duration {
+	if b.Jitter == 0 {
+		return d
+	}
+
+	j := (((b.Jitter * rand.Float64()) - (b.Jitter / 2)) / 100)
+	return d + time.Duration(float64(d)*j)
+}
+
+// attempt makes attempt number i using a Context of its own, as described
+// for RetryErr.
+func (b *Backoff) attempt(ctx context.Context, i int, attempt func(context.Context, int) (bool, error)) (bool, error) {
+	actx, cancel := context.WithCancelCause(ctx)
+
+	if b.attemptTimeout > 0 {
+		cancelAfter(actx, cancel, b.attemptTimeout, ErrAttemptTimeout)
+	}
+
+	ok, err := attempt(actx, i)
+
 	switch {
-	case b == nil:
+	case ok:
+		cancel(nil)
+	case i == b.Iterations-1:
+		cancel(ErrRetriesExhausted)
+	default:
+		cancel(ErrAttemptFailed)
+	}
+
+	return ok, err
+}
+
+// Validate checks each of the receiver's field values and returns an error
+// describing every problem found, or nil if there are none. Each problem is
+// reported as a *ValidationError and these are combined using errors.Join,
+// so a caller may test for any of them with errors.Is or errors.As.
+//
+// If the receiver is nil, ErrNilReceiver is returned.
+func (b *Backoff) Validate() error {
+	if b == nil {
 		return ErrNilReceiver
+	}
+
+	var errs []error
 
-	case b.Iterations < minIterations:
-		return ErrTooFewIterations
+	if b.Iterations < minIterations {
+		errs = append(errs, &ValidationError{Field: "Iterations", Value: b.Iterations, Err: ErrTooFewIterations})
+	}
 
+	switch {
 	case b.Coefficient == 0:
-		return ErrZeroCoefficient
+		errs = append(errs, &ValidationError{Field: "Coefficient", Value: b.Coefficient, Err: ErrZeroCoefficient})
 
 	case b.Coefficient < 0:
-		return ErrNegativeDelay
+		errs = append(errs, &ValidationError{Field: "Coefficient", Value: b.Coefficient, Err: ErrNegativeDelay})
+	}
+
+	if b.Jitter != 0 && (b.Jitter < 0 || b.Jitter >= 100) {
+		errs = append(errs, &ValidationError{Field: "Jitter", Value: b.Jitter, Err: ErrBadJitter})
+	}
 
-	case b.Jitter != 0 && (b.Jitter < 0 || b.Jitter >= 100):
-		return ErrBadJitter
+	if b.Curve != ExponentialCurve && b.Curve != CubicCurve {
+		errs = append(errs, &ValidationError{Field: "Curve", Value: b.Curve, Err: ErrUnknownCurve})
+	}
 
-	default:
-		return nil
+	if b.startWait < 0 {
+		errs = append(errs, &ValidationError{Field: "start wait", Value: b.startWait, Err: ErrNegativeDelay})
 	}
+
+	if b.initWait < 0 {
+		errs = append(errs, &ValidationError{Field: "initial wait", Value: b.initWait, Err: ErrNegativeDelay})
+	}
+
+	if b.attemptTimeout < 0 {
+		errs = append(errs, &ValidationError{Field: "attempt timeout", Value: b.attemptTimeout, Err: ErrNegativeDelay})
+	}
+
+	return errors.Join(errs...)
 }
 
 func contextDoneOr(ctx context.Context, err error) error {
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return contextErr(ctx)
 	default:
 		return err
 	}
@@ -6,14 +6,10 @@ package timetool
 
 import (
 	"context"
-	"math"
+	"errors"
 	"time"
 )
 
-const (
-	backoffPower = 3
-)
-
 // RetryWithBackoff calls the given RetryFunc a maximum of iters times until it
 // returns true. The provided context must have a defined deadline and the
 // number of iterations requested must be at least 2. Nil is returned if retry
@@ -27,28 +23,40 @@ const (
 // returned. ErrTooFewIterations will be returned if iters is less than 2.
 // If each call to retry returns false, ErrRetriesExhausted is returned.
 //
-// Deprecated: Please use *Backoff.Retry instead.
+// Deprecated: Please use *Backoff.Retry instead. BackoffForContext (or
+// Backoff.FitDeadline) provides a Backoff fitted to a Context's deadline.
 func RetryWithBackoff(ctx context.Context, iters int, retry RetryFunc) error {
 	if iters < 2 {
 		return ErrTooFewIterations
 	}
 
-	bos, err := newBackoffSession(ctx, iters)
-	if err != nil {
-		return err
+	dl, ok := ctx.Deadline()
+	if !ok {
+		return ErrMissingDeadline
+	}
+
+	ttd := dl.Sub(timeNow())
+	if ttd <= 0 {
+		return contextDoneOr(ctx, ErrTimeWarp)
 	}
 
-	for i := 0; i < iters; i++ {
-		if err := bos.sleep(ctx, i); err != nil {
-			return err
-		}
+	b := &Backoff{
+		Iterations:  iters,
+		Coefficient: ttd / time.Duration(iters*iters*iters),
+		Curve:       CubicCurve,
+	}
 
-		if retry(i) {
-			return contextDoneOr(ctx, nil)
-		}
+	if b.Coefficient <= 0 {
+		b.Coefficient = 1
 	}
 
-	return contextDoneOr(ctx, ErrRetriesExhausted)
+	// For compatibility, exhaustion is reported using the bare error value.
+	var re *RetryError
+	if err := b.Retry(ctx, retry); !errors.As(err, &re) {
+		return err
+	}
+
+	return ErrRetriesExhausted
 }
 
 // RetryWithBackoffDuration is a wrapper around RetryWithBackoff accepting
@@ -58,45 +66,3 @@ func RetryWithBackoffDuration(dur time.Duration, iters int, retry RetryFunc) err
 	defer cancel()
 	return RetryWithBackoff(ctx, iters, retry)
 }
-
-type backoffSession struct {
-	start       time.Time
-	timeout     time.Duration
-	denominator float64
-}
-
-func newBackoffSession(ctx context.Context, iters int) (*backoffSession, error) {
-	now := time.Now()
-
-	dl, ok := ctx.Deadline()
-	if !ok {
-		return nil, ErrMissingDeadline
-	}
-
-	if ttd := dl.Sub(time.Now()); ttd > 0 {
-		return &backoffSession{now, ttd, math.Pow(float64(iters), backoffPower)}, nil
-	}
-
-	return nil, contextDoneOr(ctx, ErrTimeWarp)
-}
-
-func (bo *backoffSession) sleep(ctx context.Context, i int) error {
-	if i == 0 {
-		return contextDoneOr(ctx, nil)
-	}
-
-	// START + (timeout * fraction) - NOW   =>  SLEEP
-	fx := math.Pow(float64(i), backoffPower) / bo.denominator
-	dt := time.Duration(float64(bo.timeout) * fx)
-	sd := bo.start.Add(dt).Sub(time.Now())
-
-	ch := time.After(sd)
-
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-ch:
-	}
-
-	return nil
-}
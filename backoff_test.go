@@ -0,0 +1,301 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRetryError(t *testing.T) {
+	b := &Backoff{Iterations: 3, Coefficient: time.Nanosecond}
+	bad := errors.New("bad")
+
+	attempts := 0
+	err := b.RetryErr(context.Background(), func(_ context.Context, i int) error {
+		attempts++
+		return bad
+	})
+
+	if !errors.Is(err, ErrRetriesExhausted) || !errors.Is(err, bad) {
+		t.Fatalf("RetryErr() == %v; Wanted a match for %v and %v", err, ErrRetriesExhausted, bad)
+	}
+
+	var re *RetryError
+	if !errors.As(err, &re) || re.Attempts != 3 || attempts != 3 || re.Last != bad {
+		t.Errorf("RetryErr() == %#v after %d attempts; Wanted 3 attempts with Last=%v", err, attempts, bad)
+	}
+
+	if err := b.Retry(context.Background(), func(int) bool { return false }); !errors.Is(err, ErrRetriesExhausted) {
+		t.Errorf("Retry() == %v; Wanted %v", err, ErrRetriesExhausted)
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	cases := []struct {
+		name  string
+		b     *Backoff
+		field string
+		want  error
+	}{
+		{"Iterations", &Backoff{Iterations: 1, Coefficient: 1}, "Iterations", ErrTooFewIterations},
+		{"ZeroCoefficient", &Backoff{Iterations: 2}, "Coefficient", ErrZeroCoefficient},
+		{"NegativeCoefficient", &Backoff{Iterations: 2, Coefficient: -1}, "Coefficient", ErrNegativeDelay},
+		{"Jitter", &Backoff{Iterations: 2, Coefficient: 1, Jitter: 100}, "Jitter", ErrBadJitter},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.b.Retry(context.Background(), func(int) bool { return true })
+
+			var ve *ValidationError
+			if !errors.As(err, &ve) || ve.Field != tc.field || !errors.Is(err, tc.want) {
+				t.Errorf("Retry() == %v; Wanted a *ValidationError for %s matching %v", err, tc.field, tc.want)
+			}
+		})
+	}
+
+	var nb *Backoff
+	if err := nb.Retry(context.Background(), nil); err != ErrNilReceiver {
+		t.Errorf("Retry() with nil receiver == %v; Wanted %v", err, ErrNilReceiver)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	b := (&Backoff{Iterations: 1, Coefficient: -1, Jitter: 200}).WithStartWait(-1).WithInitialWait(-1)
+
+	err := b.Validate()
+
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) || len(joined.Unwrap()) != 5 {
+		t.Fatalf("Validate() == %v; Wanted 5 joined errors", err)
+	}
+
+	for _, want := range []error{ErrTooFewIterations, ErrNegativeDelay, ErrBadJitter} {
+		if !errors.Is(err, want) {
+			t.Errorf("Validate() == %v; Wanted a match for %v", err, want)
+		}
+	}
+
+	if err := StdBackoff.Validate(); err != nil {
+		t.Errorf("StdBackoff.Validate() == %v; Wanted <nil>", err)
+	}
+
+	if _, err := (Backoff{Iterations: 1, Jitter: -1}).WithTotalDelay(time.Second); !errors.Is(err, ErrTooFewIterations) || !errors.Is(err, ErrBadJitter) {
+		t.Errorf("WithTotalDelay() == %v; Wanted matches for %v and %v", err, ErrTooFewIterations, ErrBadJitter)
+	}
+
+	_, err = Backoff{Iterations: 4}.WithStartWait(2 * time.Second).WithTotalDelay(time.Second)
+
+	var ve *ValidationError
+	if !errors.As(err, &ve) || ve.Err != ErrNegativeDelay || ve.Value != time.Second {
+		t.Errorf("WithTotalDelay(1s) with a 2s start wait == %v; Wanted a *ValidationError for %v with Value=1s", err, ErrNegativeDelay)
+	}
+}
+
+func TestRetryCause(t *testing.T) {
+	shutdown := errors.New("shutting down")
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(shutdown)
+
+	b := &Backoff{Iterations: 3, Coefficient: time.Hour}
+	err := b.Retry(ctx, func(int) bool { return false })
+
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, shutdown) {
+		t.Errorf("Retry() == %v; Wanted a match for %v and %v", err, context.Canceled, shutdown)
+	}
+}
+
+func TestRetryAttemptContext(t *testing.T) {
+	var ctxs []context.Context
+
+	b := &Backoff{Iterations: 3, Coefficient: time.Nanosecond}
+	b.RetryErr(context.Background(), func(ctx context.Context, _ int) error {
+		ctxs = append(ctxs, ctx)
+		return errors.New("failed")
+	})
+
+	want := []error{ErrAttemptFailed, ErrAttemptFailed, ErrRetriesExhausted}
+	for i, ctx := range ctxs {
+		if got := context.Cause(ctx); got != want[i] {
+			t.Errorf("attempt %d: context.Cause() == %v; Wanted %v", i, got, want[i])
+		}
+	}
+
+	defer resetTimeFuncs()
+
+	fired := make(chan time.Time)
+	close(fired)
+
+	timeNewTimer = func(time.Duration) timer {
+		return &fakeTimer{c: fired}
+	}
+
+	b = b.WithAttemptTimeout(time.Millisecond)
+	err := b.RetryErr(context.Background(), func(ctx context.Context, _ int) error {
+		<-ctx.Done()
+		return context.Cause(ctx)
+	})
+
+	var re *RetryError
+	if !errors.As(err, &re) || re.Last != ErrAttemptTimeout {
+		t.Errorf("RetryErr() == %v; Wanted a *RetryError with Last=%v", err, ErrAttemptTimeout)
+	}
+}
+
+func TestFitDeadline(t *testing.T) {
+	if _, err := BackoffForContext(context.Background(), 4, 0); err != ErrMissingDeadline {
+		t.Errorf("BackoffForContext() == %v; Wanted %v", err, ErrMissingDeadline)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	b, err := BackoffForContext(ctx, 4, 10)
+	if err != nil {
+		t.Fatalf("BackoffForContext() failed: %v", err)
+	}
+
+	// 3/4 of 200ms, less room for jitter, is split into 1+2 units of delay.
+	want := 150 * time.Millisecond * 100 / 105 / 3
+	if diff := b.Coefficient - want; diff < -time.Millisecond || diff > time.Millisecond {
+		t.Errorf("Coefficient == %v; Wanted %v", b.Coefficient, want)
+	}
+
+	attempts := 0
+	err = b.Retry(ctx, func(int) bool {
+		attempts++
+		return false
+	})
+
+	if !errors.Is(err, ErrRetriesExhausted) || attempts != 4 {
+		t.Errorf("Retry() == %v after %d attempts; Wanted %v after 4 attempts", err, attempts, ErrRetriesExhausted)
+	}
+}
+
+func TestCubicCurve(t *testing.T) {
+	defer resetTimeFuncs()
+
+	var clock time.Time
+	timeNow = func() time.Time {
+		return clock
+	}
+
+	// Each timer advances the clock by its duration and fires immediately.
+	fired := make(chan time.Time)
+	close(fired)
+	timeNewTimer = func(d time.Duration) timer {
+		clock = clock.Add(d)
+		return &fakeTimer{c: fired}
+	}
+
+	run := func(retry func(RetryFunc) error) []time.Duration {
+		start := clock
+		var offsets []time.Duration
+
+		if err := retry(func(int) bool {
+			offsets = append(offsets, clock.Sub(start).Round(10*time.Millisecond))
+			clock = clock.Add(100 * time.Millisecond) // each attempt takes a while
+			return false
+		}); !errors.Is(err, ErrRetriesExhausted) {
+			t.Errorf("retry returned %v; Wanted %v", err, ErrRetriesExhausted)
+		}
+
+		return offsets
+	}
+
+	want := []time.Duration{0, time.Second, 8 * time.Second, 27 * time.Second}
+
+	t.Run("Backoff", func(t *testing.T) {
+		clock = now
+
+		b, err := Backoff{Iterations: 4, Curve: CubicCurve}.WithTotalDelay(27 * time.Second)
+		if err != nil || b.Coefficient != time.Second {
+			t.Fatalf("WithTotalDelay(27s) == (%v, %v); Wanted Coefficient=1s", b, err)
+		}
+
+		got := run(func(rf RetryFunc) error { return b.Retry(context.Background(), rf) })
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("attempts made at %v; Wanted %v", got, want)
+		}
+	})
+
+	t.Run("RetryWithBackoff", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 64*time.Second)
+		defer cancel()
+
+		clock = time.Now()
+
+		got := run(func(rf RetryFunc) error { return RetryWithBackoff(ctx, 4, rf) })
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("attempts made at %v; Wanted %v", got, want)
+		}
+	})
+}
+
+func TestRetryFinalAttempt(t *testing.T) {
+	defer resetTimeFuncs()
+
+	fired := make(chan time.Time)
+	close(fired)
+
+	var waits []time.Duration
+	timeNewTimer = func(d time.Duration) timer {
+		waits = append(waits, d)
+		return &fakeTimer{c: fired}
+	}
+
+	b := &Backoff{Iterations: 3, Coefficient: time.Second}
+	if err := b.Retry(context.Background(), func(int) bool { return false }); !errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("Retry() == %v; Wanted %v", err, ErrRetriesExhausted)
+	}
+
+	// The only wait is the one between the 2nd and 3rd attempts; nothing
+	// follows the final attempt.
+	if len(waits) != 1 || waits[0] != time.Second {
+		t.Errorf("Retry() waited %v; Wanted [1s]", waits)
+	}
+}
+
+func TestRetryJitter(t *testing.T) {
+	defer resetTimeFuncs()
+
+	fired := make(chan time.Time)
+	close(fired)
+
+	var waits []time.Duration
+	timeNewTimer = func(d time.Duration) timer {
+		waits = append(waits, d)
+		return &fakeTimer{c: fired}
+	}
+
+	b := &Backoff{Iterations: 3, Coefficient: time.Second, Jitter: 10}
+	for i := 0; i < 20; i++ {
+		b.Retry(context.Background(), func(int) bool { return false })
+	}
+
+	// Each call waits once, for 1s ± 5%. A jittered multiple that is
+	// truncated to an integer would instead yield either 0 (and no wait at
+	// all) or exactly 1s.
+	if len(waits) != 20 {
+		t.Fatalf("Retry() waited %d times; Wanted 20", len(waits))
+	}
+
+	exact := 0
+	for _, w := range waits {
+		if w < 950*time.Millisecond || w >= 1050*time.Millisecond {
+			t.Errorf("Retry() waited %v; Wanted 1s ± 5%%", w)
+		}
+		if w == time.Second {
+			exact++
+		}
+	}
+
+	if exact == len(waits) {
+		t.Errorf("Retry() waits %v show no jitter", waits)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// budgetSlots is the number of slots into which a RetryBudget's window
+	// is divided; requests and retries age out one slot at a time.
+	budgetSlots = 10
+
+	// defaultBudgetWindow is the window used by NewRetryBudget if it is
+	// given one that is not positive.
+	defaultBudgetWindow = 10 * time.Second
+)
+
+// RetryBudget limits retries across many callers -- e.g. all of those using
+// a particular backend -- to a fraction of their first attempts. When the
+// backend is failing, this keeps callers from multiplying its load with
+// retries. A RetryBudget is shared by attaching it to each Backoff with
+// WithBudget.
+//
+// A RetryBudget is safe for concurrent use.
+type RetryBudget struct {
+	mu         sync.Mutex
+	ratio      float64
+	minRetries int
+	slotLen    time.Duration
+	slots      [budgetSlots]budgetSlot
+}
+
+type budgetSlot struct {
+	start    time.Time
+	requests int
+	retries  int
+}
+
+// NewRetryBudget returns a new RetryBudget that permits, within any span of
+// window, retries numbering up to ratio times the number of requests (i.e.
+// first attempts) plus minRetries. The allowance of minRetries lets a caller
+// making few requests retry at all. If window is not positive, a window of
+// 10 seconds is used.
+//
+// For example, NewRetryBudget(0.1, 5, time.Minute) permits at most 5
+// retries, plus 1 for every 10 requests, in any one minute span.
+func NewRetryBudget(ratio float64, minRetries int, window time.Duration) *RetryBudget {
+	if window <= 0 {
+		window = defaultBudgetWindow
+	}
+
+	if ratio < 0 {
+		ratio = 0
+	}
+
+	slotLen := window / budgetSlots
+	if slotLen <= 0 {
+		slotLen = 1
+	}
+
+	return &RetryBudget{ratio: ratio, minRetries: minRetries, slotLen: slotLen}
+}
+
+// RecordRequest records a first attempt, adding to the budget for retries.
+func (rb *RetryBudget) RecordRequest() {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	rb.slot(timeNow()).requests++
+}
+
+// AllowRetry reports whether a retry is within the budget, recording the
+// retry if so.
+func (rb *RetryBudget) AllowRetry() bool {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	now := timeNow()
+
+	var requests, retries int
+	for _, s := range rb.slots {
+		if now.Sub(s.start) < rb.slotLen*budgetSlots {
+			requests += s.requests
+			retries += s.retries
+		}
+	}
+
+	if float64(retries) >= rb.ratio*float64(requests)+float64(rb.minRetries) {
+		return false
+	}
+
+	rb.slot(now).retries++
+	return true
+}
+
+// slot returns the slot for the given time, clearing it first if it holds
+// counts from an earlier window. Must be called with rb.mu held.
+func (rb *RetryBudget) slot(now time.Time) *budgetSlot {
+	start := now.Truncate(rb.slotLen)
+	s := &rb.slots[uint64(start.UnixNano()/int64(rb.slotLen))%budgetSlots]
+
+	if !s.start.Equal(start) {
+		*s = budgetSlot{start: start}
+	}
+
+	return s
+}
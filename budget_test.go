@@ -0,0 +1,86 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryBudget(t *testing.T) {
+	defer resetTimeFuncs()
+
+	clock := now
+	timeNow = func() time.Time { return clock }
+
+	rb := NewRetryBudget(0.5, 1, 10*time.Second)
+
+	for i := 0; i < 4; i++ {
+		rb.RecordRequest()
+	}
+
+	// 4 requests * 0.5 + 1 == 3 retries
+	for i := 0; i < 3; i++ {
+		if !rb.AllowRetry() {
+			t.Fatalf("AllowRetry() #%d == false; Wanted true", i+1)
+		}
+	}
+
+	if rb.AllowRetry() {
+		t.Errorf("AllowRetry() beyond budget == true; Wanted false")
+	}
+
+	clock = clock.Add(5 * time.Second)
+	rb.RecordRequest()
+	rb.RecordRequest()
+
+	if !rb.AllowRetry() || rb.AllowRetry() {
+		t.Errorf("Expected exactly one more retry after 2 more requests")
+	}
+
+	// The first 4 requests (and 3 retries) age out of the window, leaving 2
+	// requests and 1 retry.
+	clock = clock.Add(6 * time.Second)
+
+	if !rb.AllowRetry() || rb.AllowRetry() {
+		t.Errorf("Expected exactly one more retry once older counts expired")
+	}
+}
+
+func TestRetryWithBudget(t *testing.T) {
+	rb := NewRetryBudget(0, 2, time.Hour)
+	b := (&Backoff{Iterations: 5, Coefficient: time.Nanosecond}).WithBudget(rb)
+
+	failed := errors.New("failed")
+
+	attempts := 0
+	err := b.RetryErr(context.Background(), func(context.Context, int) error {
+		attempts++
+		return failed
+	})
+
+	if !errors.Is(err, ErrRetryBudgetExceeded) || !errors.Is(err, failed) || errors.Is(err, ErrRetriesExhausted) {
+		t.Errorf("RetryErr() == %v; Wanted a match for %v and %v", err, ErrRetryBudgetExceeded, failed)
+	}
+
+	if attempts != 3 {
+		t.Errorf("RetryErr() made %d attempts; Wanted 3", attempts)
+	}
+
+	// With the budget spent, later calls get only their first attempt.
+	attempts = 0
+	err = b.Retry(context.Background(), func(int) bool {
+		attempts++
+		return false
+	})
+
+	if err != ErrRetryBudgetExceeded || attempts != 1 {
+		t.Errorf("Retry() == %v after %d attempts; Wanted %v after 1", err, attempts, ErrRetryBudgetExceeded)
+	}
+
+	if err := b.Retry(context.Background(), func(int) bool { return true }); err != nil {
+		t.Errorf("Retry() with successful first attempt == %v; Wanted <nil>", err)
+	}
+}
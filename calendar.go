@@ -0,0 +1,273 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"context"
+	"time"
+)
+
+// calendarSearchDays limits how many consecutive closed days a Calendar
+// examines while looking for open hours before concluding that there are no
+// more.
+const calendarSearchDays = 5 * 366
+
+// Shift is a span of wall clock time within a day, given as offsets from
+// midnight; e.g. Shift{9 * time.Hour, 17 * time.Hour} is 09:00 until 17:00.
+// End may be at most 24 hours.
+//
+// If End is not after Start, the Shift runs overnight until End on the
+// following day; e.g. Shift{22 * time.Hour, 6 * time.Hour} is 22:00 until
+// 06:00 the next morning. An overnight Shift belongs to the day on which it
+// starts, so it is omitted if that day is a holiday but not if the next day
+// is.
+type Shift struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Calendar defines the hours during which a business is open, for measuring
+// and scheduling in "business time".
+type Calendar struct {
+	// Location is the time zone in which Week and Holidays are interpreted.
+	// If nil, time.Local is used.
+	Location *time.Location
+
+	// Week holds the open hours for each day of the week, indexed by
+	// time.Weekday. A day with no Shifts is closed.
+	Week [7][]Shift
+
+	// Holidays lists dates on which the Calendar is closed regardless of
+	// Week. Only the year, month and day of each (in its own Location) are
+	// considered.
+	Holidays []time.Time
+}
+
+// NewCalendar returns a Calendar open from start until end on each of the
+// given days in loc. For example, a typical office would be:
+//
+//	NewCalendar(loc, 9*time.Hour, 17*time.Hour, time.Monday, time.Tuesday,
+//		time.Wednesday, time.Thursday, time.Friday)
+func NewCalendar(loc *time.Location, start, end time.Duration, days ...time.Weekday) *Calendar {
+	c := &Calendar{Location: loc}
+
+	for _, d := range days {
+		c.Week[d] = []Shift{{Start: start, End: end}}
+	}
+
+	return c
+}
+
+// IsOpen reports whether c is open at t.
+func (c *Calendar) IsOpen(t time.Time) bool {
+	y, m, d := t.In(c.loc()).Date()
+
+	for _, r := range c.openOn(y, m, d) {
+		if r.Contains(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NextOpen returns t if c is open at t or else the time it next opens. The
+// zero Time is returned if c has no open hours.
+func (c *Calendar) NextOpen(t time.Time) time.Time {
+	var next time.Time
+
+	c.walk(t, false, func(r Range) bool {
+		if r.End.After(t) {
+			next = latest(r.Start, t)
+			return false
+		}
+		return true
+	})
+
+	return next
+}
+
+// Add returns the time at which d of open time will have elapsed on c,
+// counting from t; e.g. given a Friday afternoon, Add might return a time on
+// the following Monday. Time during which c is closed does not count. If d is
+// negative, time is counted backward from t instead. The zero Time is
+// returned if c has no open hours.
+//
+// If d is zero, Add is equivalent to NextOpen.
+func (c *Calendar) Add(t time.Time, d time.Duration) time.Time {
+	var (
+		out  time.Time
+		back = d < 0
+	)
+
+	if back {
+		d = -d
+	}
+
+	c.walk(t, back, func(r Range) bool {
+		if back {
+			if !r.Start.Before(t) {
+				return true
+			}
+
+			end := earliest(r.End, t)
+			if avail := end.Sub(r.Start); d > avail {
+				d -= avail
+				return true
+			}
+
+			out = end.Add(-d)
+			return false
+		}
+
+		if !r.End.After(t) {
+			return true
+		}
+
+		start := latest(r.Start, t)
+		if avail := r.End.Sub(start); d > avail {
+			d -= avail
+			return true
+		}
+
+		out = start.Add(d)
+		return false
+	})
+
+	return out
+}
+
+// Between returns the amount of open time on c from t1 up to t2. The result
+// is negative if t2 is before t1.
+func (c *Calendar) Between(t1, t2 time.Time) time.Duration {
+	if t2.Before(t1) {
+		return -c.Between(t2, t1)
+	}
+
+	var (
+		total time.Duration
+		span  = Range{Start: t1, End: t2}
+	)
+
+	c.walk(t1, false, func(r Range) bool {
+		if !r.Start.Before(t2) {
+			return false
+		}
+
+		total += r.Intersect(span).Duration()
+		return true
+	})
+
+	return total
+}
+
+func (c *Calendar) loc() *time.Location {
+	if c.Location == nil {
+		return time.Local
+	}
+	return c.Location
+}
+
+// walk passes each of c's open Ranges to fn, in chronological order starting
+// with those on the date of t (or in reverse, if back is true), until fn
+// returns false or calendarSearchDays consecutive closed days have been
+// examined.
+func (c *Calendar) walk(t time.Time, back bool, fn func(Range) bool) {
+	y, m, d := t.In(c.loc()).Date()
+
+	step := 1
+	if back {
+		step = -1
+	}
+
+	for i, closed := 0, 0; closed < calendarSearchDays; i++ {
+		ranges := c.openOn(y, m, d+i*step)
+		if len(ranges) == 0 {
+			closed++
+			continue
+		}
+
+		closed = 0
+
+		for j := range ranges {
+			if back {
+				j = len(ranges) - 1 - j
+			}
+
+			if !fn(ranges[j]) {
+				return
+			}
+		}
+	}
+}
+
+// openOn returns the Ranges during which c is open on the given date. The day
+// may be out of range for the month, as with time.Date.
+//
+// The Ranges are confined to the date itself, so that those of consecutive
+// dates never overlap: an overnight Shift contributes the part before
+// midnight to the date on which it starts and the remainder to the next.
+func (c *Calendar) openOn(year int, month time.Month, day int) []Range {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	year, month, day = date.Date()
+
+	today := Range{Start: c.wallTime(year, month, day, 0), End: c.wallTime(year, month, day+1, 0)}
+
+	var rs RangeSet
+	for _, from := range []time.Time{date.AddDate(0, 0, -1), date} {
+		y, m, d := from.Date()
+		if c.isHoliday(y, m, d) {
+			continue
+		}
+
+		for _, s := range c.Week[from.Weekday()] {
+			end := d
+			if s.End <= s.Start {
+				end++
+			}
+
+			r := Range{Start: c.wallTime(y, m, d, s.Start), End: c.wallTime(y, m, end, s.End)}
+			rs.Add(r.Intersect(today))
+		}
+	}
+
+	return rs.Ranges()
+}
+
+func (c *Calendar) isHoliday(year int, month time.Month, day int) bool {
+	for _, h := range c.Holidays {
+		if hy, hm, hd := h.Date(); hy == year && hm == month && hd == day {
+			return true
+		}
+	}
+	return false
+}
+
+// wallTime returns the time at the given wall clock offset from midnight.
+func (c *Calendar) wallTime(year int, month time.Month, day int, off time.Duration) time.Time {
+	h, m, s, ns := off/time.Hour, off%time.Hour/time.Minute, off%time.Minute/time.Second, off%time.Second
+	return time.Date(year, month, day, int(h), int(m), int(s), int(ns), c.loc())
+}
+
+// SleepOpen pauses the current goroutine until d of open time has elapsed on
+// cal, counting from now, while honoring ctx as with Sleep; the wait is, in
+// effect, paused whenever cal is closed. Since cal's hours are bound to the
+// wall clock, the wait tracks the wall clock as with SleepUntilWall.
+//
+// If cal has no open hours, ErrNeverOpen is returned immediately.
+func SleepOpen(ctx context.Context, cal *Calendar, d time.Duration) error {
+	t := cal.Add(timeNow(), d)
+	if t.IsZero() {
+		return ErrNeverOpen
+	}
+
+	return SleepUntilWall(ctx, t, 0)
+}
+
+// SleepUntilOpen pauses the current goroutine until cal is open, while
+// honoring ctx as with Sleep. It returns immediately if cal is already open.
+//
+// If cal has no open hours, ErrNeverOpen is returned immediately.
+func SleepUntilOpen(ctx context.Context, cal *Calendar) error {
+	return SleepOpen(ctx, cal, 0)
+}
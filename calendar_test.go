@@ -0,0 +1,198 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCalendar(t *testing.T) {
+	la := mustLoadLocation(t, "America/Los_Angeles")
+
+	cal := NewCalendar(la, 9*time.Hour, 17*time.Hour, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+	cal.Week[time.Saturday] = []Shift{{10 * time.Hour, 12 * time.Hour}, {13 * time.Hour, 14 * time.Hour}}
+	cal.Holidays = []time.Time{time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)}
+
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2024, month, day, hour, min, 0, 0, la)
+	}
+
+	for _, tc := range []struct {
+		t    time.Time
+		want bool
+	}{
+		{at(12, 23, 9, 0), true},   // Monday
+		{at(12, 23, 17, 0), false}, // closing time
+		{at(12, 25, 12, 0), false}, // holiday
+		{at(12, 28, 12, 30), false},
+		{at(12, 28, 13, 30), true},
+		{at(12, 29, 12, 0), false}, // Sunday
+	} {
+		if got := cal.IsOpen(tc.t); got != tc.want {
+			t.Errorf("IsOpen(%v) == %t; Wanted %t", tc.t, got, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		t, want time.Time
+	}{
+		{at(12, 23, 10, 0), at(12, 23, 10, 0)},
+		{at(12, 23, 18, 0), at(12, 24, 9, 0)},
+		{at(12, 24, 17, 0), at(12, 26, 9, 0)},
+		{at(12, 28, 12, 15), at(12, 28, 13, 0)},
+		{at(12, 28, 15, 0), at(12, 30, 9, 0)},
+	} {
+		if got := cal.NextOpen(tc.t); !got.Equal(tc.want) {
+			t.Errorf("NextOpen(%v) == %v; Wanted %v", tc.t, got, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		t    time.Time
+		d    time.Duration
+		want time.Time
+	}{
+		{at(12, 23, 10, 0), 4 * time.Hour, at(12, 23, 14, 0)},
+		{at(12, 23, 15, 0), 2 * time.Hour, at(12, 23, 17, 0)},
+		{at(12, 24, 15, 0), 4 * time.Hour, at(12, 26, 11, 0)},
+		{at(12, 27, 16, 0), 3 * time.Hour, at(12, 28, 12, 0)},
+		{at(12, 27, 16, 0), 5 * time.Hour, at(12, 30, 10, 0)},
+		{at(12, 26, 11, 0), -4 * time.Hour, at(12, 24, 15, 0)},
+		{at(12, 30, 10, 0), -5 * time.Hour, at(12, 27, 16, 0)},
+		{at(12, 29, 12, 0), 0, at(12, 30, 9, 0)},
+		// 2024-03-10 is a Sunday when DST begins; hours remain 9-5 wall time.
+		{at(3, 9, 13, 30), 2 * time.Hour, at(3, 11, 10, 30)},
+	} {
+		if got := cal.Add(tc.t, tc.d); !got.Equal(tc.want) {
+			t.Errorf("Add(%v, %v) == %v; Wanted %v", tc.t, tc.d, got, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		t1, t2 time.Time
+		want   time.Duration
+	}{
+		{at(12, 23, 10, 0), at(12, 23, 14, 0), 4 * time.Hour},
+		{at(12, 23, 8, 0), at(12, 31, 8, 0), 5*8*time.Hour + 3*time.Hour},
+		{at(12, 26, 11, 0), at(12, 24, 15, 0), -4 * time.Hour},
+		{at(12, 29, 0, 0), at(12, 30, 9, 0), 0},
+	} {
+		if got := cal.Between(tc.t1, tc.t2); got != tc.want {
+			t.Errorf("Between(%v, %v) == %v; Wanted %v", tc.t1, tc.t2, got, tc.want)
+		}
+	}
+
+	if got := (&Calendar{}).NextOpen(at(1, 1, 0, 0)); !got.IsZero() {
+		t.Errorf("NextOpen() for closed Calendar == %v; Wanted zero Time", got)
+	}
+}
+
+func TestCalendarOvernight(t *testing.T) {
+	la := mustLoadLocation(t, "America/Los_Angeles")
+
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 12, day, hour, 0, 0, 0, la)
+	}
+
+	// Friday night until Saturday morning.
+	cal := &Calendar{Location: la}
+	cal.Week[time.Friday] = []Shift{{22 * time.Hour, 6 * time.Hour}}
+	cal.Week[time.Saturday] = []Shift{{2 * time.Hour, 8 * time.Hour}}
+
+	for _, tc := range []struct {
+		t    time.Time
+		want bool
+	}{
+		{at(27, 21), false},
+		{at(27, 23), true},
+		{at(28, 1), true},
+		{at(28, 7), true},
+		{at(28, 8), false},
+	} {
+		if got := cal.IsOpen(tc.t); got != tc.want {
+			t.Errorf("IsOpen(%v) == %t; Wanted %t", tc.t, got, tc.want)
+		}
+	}
+
+	if got, want := cal.NextOpen(at(27, 12)), at(27, 22); !got.Equal(want) {
+		t.Errorf("NextOpen(%v) == %v; Wanted %v", at(27, 12), got, want)
+	}
+
+	if got, want := cal.Add(at(27, 23), 3*time.Hour), at(28, 2); !got.Equal(want) {
+		t.Errorf("Add(%v, 3h) == %v; Wanted %v", at(27, 23), got, want)
+	}
+
+	if got, want := cal.Add(at(28, 2), -3*time.Hour), at(27, 23); !got.Equal(want) {
+		t.Errorf("Add(%v, -3h) == %v; Wanted %v", at(28, 2), got, want)
+	}
+
+	// The overlapping Saturday Shift is not counted twice.
+	if got, want := cal.Between(at(27, 12), at(28, 12)), 10*time.Hour; got != want {
+		t.Errorf("Between(%v, %v) == %v; Wanted %v", at(27, 12), at(28, 12), got, want)
+	}
+
+	// A Shift belongs to the day on which it starts.
+	cal.Holidays = []time.Time{time.Date(2024, 12, 28, 0, 0, 0, 0, time.UTC)}
+	if !cal.IsOpen(at(28, 1)) || cal.IsOpen(at(28, 7)) {
+		t.Errorf("with Saturday a holiday, IsOpen() == (%t, %t) at 01:00 and 07:00; Wanted (true, false)", cal.IsOpen(at(28, 1)), cal.IsOpen(at(28, 7)))
+	}
+
+	cal.Holidays = []time.Time{time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC)}
+	if cal.IsOpen(at(28, 1)) || !cal.IsOpen(at(28, 3)) {
+		t.Errorf("with Friday a holiday, IsOpen() == (%t, %t) at 01:00 and 03:00; Wanted (false, true)", cal.IsOpen(at(28, 1)), cal.IsOpen(at(28, 3)))
+	}
+}
+
+func TestCalendarLongAdd(t *testing.T) {
+	cal := NewCalendar(time.UTC, 9*time.Hour, 17*time.Hour, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+
+	// Roughly ten years of business hours; far more days than are searched
+	// for the next open hours.
+	start := time.Date(2024, 12, 23, 9, 0, 0, 0, time.UTC)
+	d := 20000 * time.Hour
+
+	end := cal.Add(start, d)
+	if end.IsZero() {
+		t.Fatalf("Add(%v, %v) == zero Time; Wanted a time about ten years later", start, d)
+	}
+
+	if got := cal.Between(start, end); got != d {
+		t.Errorf("Between(%v, %v) == %v; Wanted %v", start, end, got, d)
+	}
+
+	if got := cal.Add(end, -d); !got.Equal(start) {
+		t.Errorf("Add(%v, %v) == %v; Wanted %v", end, -d, got, start)
+	}
+}
+
+func TestSleepOpen(t *testing.T) {
+	defer resetTimeFuncs()
+
+	la := mustLoadLocation(t, "America/Los_Angeles")
+	cal := NewCalendar(la, 9*time.Hour, 17*time.Hour, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+
+	fired := make(chan time.Time)
+	close(fired)
+
+	clock := time.Date(2024, 12, 27, 16, 0, 0, 0, la) // Friday
+	timeNow = func() time.Time { return clock }
+	timeNewTimer = func(d time.Duration) timer {
+		clock = clock.Add(d)
+		return &fakeTimer{c: fired}
+	}
+
+	if err := SleepOpen(context.Background(), cal, 2*time.Hour); err != nil {
+		t.Fatalf("SleepOpen() == %v; Wanted <nil>", err)
+	}
+
+	if want := time.Date(2024, 12, 30, 10, 0, 0, 0, la); !clock.Equal(want) {
+		t.Errorf("SleepOpen() woke at %v; Wanted %v", clock, want)
+	}
+
+	if err := SleepUntilOpen(context.Background(), &Calendar{}); !errors.Is(err, ErrNeverOpen) {
+		t.Errorf("SleepUntilOpen() == %v; Wanted %v", err, ErrNeverOpen)
+	}
+}
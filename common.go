@@ -22,20 +22,78 @@
 // precious of commodities: time.
 package timetool // import "toolman.org/time/timetool"
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"time"
+)
 
 func init() {
 	resetTimeFuncs()
 }
 
 var (
-	timeAfter func(time.Duration) <-chan time.Time
-	timeNow   func() time.Time
-	timeSleep func(time.Duration)
+	timeNewTimer func(time.Duration) timer
+	timeNow      func() time.Time
+	timeSleep    func(time.Duration)
 )
 
 func resetTimeFuncs() {
-	timeAfter = time.After
+	timeNewTimer = newStdTimer
 	timeNow = time.Now
 	timeSleep = time.Sleep
 }
+
+// contextErr returns ctx.Err() or, if ctx was cancelled with a cause other
+// than ctx.Err() itself (see context.WithCancelCause), an error wrapping both
+// ctx.Err() and that cause.
+func contextErr(ctx context.Context) error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+
+	if cause := context.Cause(ctx); cause != nil && cause != err {
+		return fmt.Errorf("%w: %w", err, cause)
+	}
+
+	return err
+}
+
+// cancelAfter arranges for cancel, which must cancel ctx, to be called with
+// cause once d has elapsed according to the package's timer hook. The timer
+// is released as soon as ctx is done, so the caller need only ensure that
+// ctx is eventually cancelled.
+func cancelAfter(ctx context.Context, cancel context.CancelCauseFunc, d time.Duration, cause error) {
+	t := timeNewTimer(d)
+
+	go func() {
+		defer t.Stop()
+
+		select {
+		case <-t.Chan():
+			cancel(cause)
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// timer is the subset of a *time.Timer's behavior used by this package. It
+// exists so that tests may substitute their own timers.
+type timer interface {
+	Chan() <-chan time.Time
+	Stop() bool
+	Reset(time.Duration) bool
+}
+
+type stdTimer struct {
+	*time.Timer
+}
+
+func newStdTimer(d time.Duration) timer {
+	return stdTimer{time.NewTimer(d)}
+}
+
+func (st stdTimer) Chan() <-chan time.Time {
+	return st.C
+}
@@ -0,0 +1,387 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule describes a recurring series of activation times.
+type Schedule interface {
+	// Next returns the first activation time strictly after the given time
+	// or, if there is no such time, the zero Time.
+	Next(after time.Time) time.Time
+}
+
+// ParseSchedule parses spec as either a standard cron expression or a
+// predefined descriptor and returns the equivalent Schedule.
+//
+// A cron expression has 5 fields (minute, hour, day-of-month, month and
+// day-of-week) or 6 fields, where a leading seconds field is added. Each field
+// may be "*", a single value, a range ("a-b") or a comma separated list of
+// these; values and ranges may be followed by a step ("/n"). Months and days
+// of the week may also be given by their three letter English names (e.g.
+// "JAN" or "mon") and a day-of-week of 7 is treated as Sunday. The
+// day-of-month and day-of-week fields may also be "?", which is equivalent to
+// "*". As with cron, if both of these fields are restricted, a day matching
+// either of them will match.
+//
+// The following descriptors are also recognized:
+//
+//	@yearly (or @annually)  Once a year, at midnight on January 1st
+//	@monthly                Once a month, at midnight on the 1st
+//	@weekly                 Once a week, at midnight on Sunday
+//	@daily (or @midnight)   Once a day, at midnight
+//	@hourly                 Once an hour, at the top of the hour
+//	@every <duration>       Repeatedly, every <duration> after the given time
+//
+// The <duration> for @every may be in any form accepted by ParseDuration.
+//
+// Cron expressions are evaluated against the wall clock of the Location of the
+// time passed to Next. Since the schedule advances in absolute time, an
+// activation in the hour skipped when the clock springs forward does not occur
+// and an activation in an hour repeated when the clock falls back occurs twice.
+//
+// An error wrapping ErrBadSchedule is returned if spec cannot be parsed.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, "@") {
+		return parseDescriptor(spec)
+	}
+
+	fields := strings.Fields(spec)
+
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w: expected 5 or 6 fields; found %d in %q", ErrBadSchedule, len(fields), spec)
+	}
+
+	cs := &cronSchedule{}
+
+	for i, f := range fields {
+		bits, star, err := cronFields[i].parse(f)
+		if err != nil {
+			return nil, err
+		}
+
+		switch i {
+		case 0:
+			cs.second = bits
+		case 1:
+			cs.minute = bits
+		case 2:
+			cs.hour = bits
+		case 3:
+			cs.dom, cs.domStar = bits, star
+		case 4:
+			cs.month = bits
+		case 5:
+			cs.dow, cs.dowStar = bits, star
+		}
+	}
+
+	return cs, nil
+}
+
+// MustParseSchedule is a wrapper around ParseSchedule that will panic if an
+// error is returned.
+func MustParseSchedule(spec string) Schedule {
+	s, err := ParseSchedule(spec)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+func parseDescriptor(spec string) (Schedule, error) {
+	if ds, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		return ParseSchedule(ds)
+	}
+
+	// The descriptor itself is case-insensitive but its duration may not be
+	// (e.g. ISO 8601 durations require an upper case 'P').
+	if f := strings.Fields(spec); len(f) > 1 && strings.ToLower(f[0]) == "@every" {
+		dur, err := ParseDuration(strings.Join(f[1:], " "))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBadSchedule, err)
+		}
+
+		if dur <= 0 {
+			return nil, fmt.Errorf("%w: @every duration must be positive", ErrBadSchedule)
+		}
+
+		return everySchedule(dur), nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown descriptor %q", ErrBadSchedule, spec)
+}
+
+// everySchedule is a Schedule that activates at a fixed interval.
+type everySchedule time.Duration
+
+func (es everySchedule) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(es))
+}
+
+// cronSchedule is a Schedule defined by a cron expression. Each field is a
+// bitmask of the values it matches.
+type cronSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	domStar, dowStar                      bool
+}
+
+// Next implements Schedule. The search for a matching time is abandoned, and
+// the zero Time returned, if none is found within 5 years.
+func (cs *cronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+
+	// Start at the next whole second (which also drops any monotonic clock
+	// reading).
+	t := after.Round(0).Truncate(time.Second).Add(time.Second)
+	limit := t.Year() + 5
+
+	// The first time a field is advanced, all of its less significant fields
+	// are zeroed.
+	zeroed := false
+
+wrap:
+	for t.Year() <= limit {
+		for !hasBit(cs.month, int(t.Month())) {
+			if !zeroed {
+				zeroed = true
+				t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+			}
+
+			if t = t.AddDate(0, 1, 0); t.Month() == time.January {
+				continue wrap
+			}
+		}
+
+		for !cs.dayMatches(t) {
+			if !zeroed {
+				zeroed = true
+				t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+			}
+
+			if t = t.AddDate(0, 0, 1); t.Day() == 1 {
+				continue wrap
+			}
+		}
+
+		for !hasBit(cs.hour, t.Hour()) {
+			if !zeroed {
+				zeroed = true
+				t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+			}
+
+			if t = t.Add(time.Hour); t.Hour() == 0 {
+				continue wrap
+			}
+		}
+
+		for !hasBit(cs.minute, t.Minute()) {
+			if !zeroed {
+				zeroed = true
+				t = t.Truncate(time.Minute)
+			}
+
+			if t = t.Add(time.Minute); t.Minute() == 0 {
+				continue wrap
+			}
+		}
+
+		for !hasBit(cs.second, t.Second()) {
+			if t = t.Add(time.Second); t.Second() == 0 {
+				continue wrap
+			}
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (cs *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := hasBit(cs.dom, t.Day())
+	dowMatch := hasBit(cs.dow, int(t.Weekday()))
+
+	if cs.domStar || cs.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+func hasBit(bits uint64, n int) bool {
+	return bits&(1<<uint(n)) != 0
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+	question bool
+}
+
+var cronFields = [6]cronField{
+	{name: "second", min: 0, max: 59},
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31, question: true},
+	{name: "month", min: 1, max: 12, names: []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day-of-week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}, question: true},
+}
+
+// parse returns a bitmask of the values matched by spec and whether spec was
+// an unrestricted wildcard.
+func (cf *cronField) parse(spec string) (uint64, bool, error) {
+	var bits uint64
+	star := false
+
+	for _, part := range strings.Split(spec, ",") {
+		rng, step, hasStep := strings.Cut(part, "/")
+
+		lo, hi := cf.min, cf.max
+		switch {
+		case rng == "*" || (cf.question && rng == "?"):
+			star = !hasStep && !strings.Contains(spec, ",")
+
+		default:
+			a, b, isRange := strings.Cut(rng, "-")
+
+			var err error
+			if lo, err = cf.value(a); err != nil {
+				return 0, false, err
+			}
+
+			switch {
+			case isRange:
+				if hi, err = cf.value(b); err != nil {
+					return 0, false, err
+				}
+			case !hasStep:
+				hi = lo
+			}
+		}
+
+		n := 1
+		if hasStep {
+			var err error
+			if n, err = strconv.Atoi(step); err != nil || n <= 0 {
+				return 0, false, fmt.Errorf("%w: bad %s step %q", ErrBadSchedule, cf.name, step)
+			}
+		}
+
+		if lo > hi {
+			return 0, false, fmt.Errorf("%w: bad %s range %q", ErrBadSchedule, cf.name, rng)
+		}
+
+		for v := lo; v <= hi; v += n {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	if cf.name == "day-of-week" && hasBit(bits, 7) {
+		bits = bits&^(1<<7) | 1
+	}
+
+	return bits, star, nil
+}
+
+func (cf *cronField) value(s string) (int, error) {
+	for i, n := range cf.names {
+		if n != "" && strings.EqualFold(s, n) {
+			return i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < cf.min || v > cf.max {
+		return 0, fmt.Errorf("%w: bad %s value %q", ErrBadSchedule, cf.name, s)
+	}
+
+	return v, nil
+}
+
+// CronTicker holds a channel that delivers "ticks" of a clock at each
+// activation of a Schedule. Each tick is sent on either C or T (see
+// NormalTicker).
+type CronTicker struct {
+	C chan time.Time
+	T chan Tick
+	ticker
+}
+
+// NewCronTicker returns a new CronTicker containing a channel that will send
+// the current time on the channel at each activation of sched. Activation
+// times are rechecked against the clock periodically so that the ticker is
+// not misled by a host suspension or a stepped clock.
+//
+// By default, the ticker will drop ticks to make up for slow receivers (see
+// WithOverrunPolicy) and will continue to send values to its channel until
+// the Stop method is called, the given context is expired or sched has no
+// further activations (in which case its Err method returns
+// ErrScheduleExhausted).
+func NewCronTicker(ctx context.Context, sched Schedule, opts ...TickerOption) *CronTicker {
+	ct := &CronTicker{
+		C: make(chan time.Time),
+		T: make(chan Tick),
+	}
+
+	ct.ticker = newTicker(ct.C, ct.T, sched.Next, wallRecheck, opts)
+
+	go ct.run(ctx)
+
+	return ct
+}
+
+// RunSchedule calls fn at each activation of sched, using SleepUntilWall to
+// wait between activations, until fn returns a non-nil error or ctx is
+// cancelled. Activations that occur while fn is running are skipped.
+//
+// RunSchedule returns the error from fn, ctx.Err() if the Context is
+// cancelled, or ErrScheduleExhausted if sched has no further activations.
+func RunSchedule(ctx context.Context, sched Schedule, fn func(context.Context) error) error {
+	var last time.Time
+
+	for {
+		from := timeNow()
+		if from.Before(last) {
+			from = last
+		}
+
+		next := sched.Next(from)
+		if next.IsZero() {
+			return ErrScheduleExhausted
+		}
+
+		if err := SleepUntilWall(ctx, next, 0); err != nil {
+			return err
+		}
+
+		if err := fn(ctx); err != nil {
+			return err
+		}
+
+		last = next
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	nyc := mustLoadLocation(t, "America/New_York")
+	from := time.Date(2024, 5, 1, 10, 7, 12, 500, time.UTC) // a Wednesday
+
+	cases := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", from, time.Date(2024, 5, 1, 10, 8, 0, 0, time.UTC)},
+		{"* * * * * *", from, time.Date(2024, 5, 1, 10, 7, 13, 0, time.UTC)},
+		{"*/15 * * * *", from, time.Date(2024, 5, 1, 10, 15, 0, 0, time.UTC)},
+		{"30 2 * * *", from, time.Date(2024, 5, 2, 2, 30, 0, 0, time.UTC)},
+		{"0 9 * * MON-FRI", time.Date(2024, 5, 3, 9, 0, 0, 0, time.UTC), time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan ?", from, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", from, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", from, time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", from, time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC)},
+		{"15,45 10-12/2 * * *", from, time.Date(2024, 5, 1, 10, 15, 0, 0, time.UTC)},
+		{"0 0 30 2 *", from, time.Time{}},
+		{"@daily", from, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", from, time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)},
+		{"@every 90s", from, from.Add(90 * time.Second)},
+		{"@EVERY \t 1 hour  30 minutes", from, from.Add(90 * time.Minute)},
+		{"@Every PT2H", from, from.Add(2 * time.Hour)},
+		{"@DAILY", from, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", time.Date(2024, 3, 10, 0, 0, 0, 0, nyc), time.Date(2024, 3, 11, 2, 30, 0, 0, nyc)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.spec, func(t *testing.T) {
+			s, err := ParseSchedule(tc.spec)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) failed: %v", tc.spec, err)
+			}
+
+			if got := s.Next(tc.from); !got.Equal(tc.want) {
+				t.Errorf("ParseSchedule(%q).Next(%v) == %v; Wanted %v", tc.spec, tc.from, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "? * * * *", "@never", "@every", "@every -1s", "@every soon", "@everyday"} {
+		if _, err := ParseSchedule(spec); !errors.Is(err, ErrBadSchedule) {
+			t.Errorf("ParseSchedule(%q) == %v; Wanted %v", spec, err, ErrBadSchedule)
+		}
+	}
+}
+
+func TestRunSchedule(t *testing.T) {
+	stop := errors.New("stop")
+	runs := 0
+
+	err := RunSchedule(context.Background(), MustParseSchedule("@every 10ms"), func(context.Context) error {
+		if runs++; runs == 3 {
+			return stop
+		}
+		return nil
+	})
+
+	if err != stop || runs != 3 {
+		t.Errorf("RunSchedule() == %v after %d runs; Wanted %v after 3 runs", err, runs, stop)
+	}
+}
+
+func TestCronTicker(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	ct := NewCronTicker(ctx, MustParseSchedule("@every 20ms"))
+	defer ct.Stop()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-ct.C:
+		case <-ctx.Done():
+			t.Fatalf("tick %d never arrived", i)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// Day is 24 hours. Note that, due to daylight saving time transitions,
+	// not every calendar day is this long (see Period for calendar aware
+	// arithmetic).
+	Day = 24 * time.Hour
+
+	// Week is 7 Days.
+	Week = 7 * Day
+)
+
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond, "nsec": time.Nanosecond, "nanosecond": time.Nanosecond, "nanoseconds": time.Nanosecond,
+	"us": time.Microsecond, "µs": time.Microsecond, "μs": time.Microsecond, "usec": time.Microsecond, "microsecond": time.Microsecond, "microseconds": time.Microsecond,
+	"ms": time.Millisecond, "msec": time.Millisecond, "millisecond": time.Millisecond, "milliseconds": time.Millisecond,
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": Day, "day": Day, "days": Day,
+	"w": Week, "wk": Week, "wks": Week, "week": Week, "weeks": Week,
+}
+
+// ParseDuration parses a duration string. In addition to everything accepted
+// by time.ParseDuration (e.g. "1h30m" or "1.5h"), it accepts:
+//
+//   - Days and weeks, where a day is always 24 hours and a week is always
+//     7 days (e.g. "3d" or "1w2d").
+//   - Spelled out units, separated by spaces, commas and/or "and" (e.g.
+//     "1 hour 30 minutes" or "2 days, 3 hrs and 5 secs").
+//   - ISO 8601 durations, limited to weeks, days, hours, minutes and seconds,
+//     where only the time components may be fractional (e.g. "P1DT2H" or
+//     "PT0.5S"). See ParsePeriod for durations that include years or months.
+//
+// Any of these may be preceded by a sign. Unit names are not case sensitive.
+//
+// An error wrapping ErrBadDuration is returned if s cannot be parsed or if
+// its value would overflow a time.Duration.
+func ParseDuration(s string) (time.Duration, error) {
+	in := strings.TrimSpace(s)
+
+	neg := false
+	if in != "" && (in[0] == '-' || in[0] == '+') {
+		neg = in[0] == '-'
+		in = in[1:]
+	}
+
+	var (
+		d   time.Duration
+		err error
+	)
+
+	switch {
+	case in == "0":
+		return 0, nil
+
+	case strings.HasPrefix(in, "P"):
+		d, err = parseISODurationOnly(in[1:], neg)
+
+	default:
+		d, err = parseHumanDuration(in, neg)
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("%w %q: %v", ErrBadDuration, s, err)
+	}
+
+	return d, nil
+}
+
+// MustParseDuration is a wrapper around ParseDuration that will panic if an
+// error is returned.
+func MustParseDuration(s string) time.Duration {
+	d, err := ParseDuration(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// FormatDuration returns a compact string representation of d using weeks,
+// days, hours, minutes and (possibly fractional) seconds, omitting any that
+// are zero -- e.g. "1w2d3h4m5.5s". Durations of less than a second use the
+// smallest appropriate unit, as with time.Duration's String method (e.g.
+// "1.5ms"). The result may be parsed by ParseDuration to recover d.
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+
+	var sb strings.Builder
+
+	u := uint64(d)
+	if d < 0 {
+		sb.WriteByte('-')
+		u = -u
+	}
+
+	for _, unit := range []struct {
+		size time.Duration
+		name string
+	}{{Week, "w"}, {Day, "d"}, {time.Hour, "h"}, {time.Minute, "m"}} {
+		if n := u / uint64(unit.size); n > 0 {
+			sb.WriteString(strconv.FormatUint(n, 10))
+			sb.WriteString(unit.name)
+			u -= n * uint64(unit.size)
+		}
+	}
+
+	if u > 0 {
+		sb.WriteString(time.Duration(u).String())
+	}
+
+	return sb.String()
+}
+
+// parseHumanDuration parses s as a sequence of numbers and units, whose total
+// is negated if neg is true.
+func parseHumanDuration(s string, neg bool) (time.Duration, error) {
+	var total time.Duration
+
+	s = trimDurationSeparators(s)
+	if s == "" {
+		return 0, fmt.Errorf("missing value")
+	}
+
+	for s != "" {
+		i := strings.IndexFunc(s, func(r rune) bool { return r != '.' && !unicode.IsDigit(r) })
+		if i == 0 {
+			return 0, fmt.Errorf("expected number at %q", s)
+		}
+
+		if i < 0 {
+			return 0, fmt.Errorf("missing unit after %q", s)
+		}
+
+		num := s[:i]
+		s = strings.TrimLeft(s[i:], " ")
+
+		j := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && r != 'µ' })
+		if j < 0 {
+			j = len(s)
+		}
+
+		unit, ok := durationUnits[strings.ToLower(s[:j])]
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q", s[:j])
+		}
+
+		d, err := scaleDuration(num, unit, neg)
+		if err != nil {
+			return 0, err
+		}
+
+		if total, ok = addChecked(total, d); !ok {
+			return 0, fmt.Errorf("overflow")
+		}
+
+		s = trimDurationSeparators(s[j:])
+	}
+
+	return total, nil
+}
+
+func trimDurationSeparators(s string) string {
+	for {
+		t := strings.TrimLeft(s, " \t,")
+		if rest, ok := strings.CutPrefix(strings.ToLower(t), "and "); ok {
+			t = t[len(t)-len(rest):]
+		}
+
+		if t == s {
+			return s
+		}
+
+		s = t
+	}
+}
+
+// scaleDuration returns num (a decimal number, possibly with a fractional
+// part) multiplied by unit and, if neg is true, negated. The product is
+// formed as an unsigned magnitude so that a negated result may reach
+// math.MinInt64, whose magnitude exceeds math.MaxInt64.
+func scaleDuration(num string, unit time.Duration, neg bool) (time.Duration, error) {
+	whole, frac, _ := strings.Cut(num, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("bad number %q", num)
+	}
+
+	limit := uint64(math.MaxInt64)
+	if neg {
+		limit++
+	}
+
+	var w uint64
+	if whole != "" {
+		var err error
+		if w, err = strconv.ParseUint(whole, 10, 64); err != nil || w > limit/uint64(unit) {
+			return 0, fmt.Errorf("overflow")
+		}
+	}
+
+	m := w * uint64(unit)
+
+	if frac != "" {
+		f, err := strconv.ParseFloat("0."+frac, 64)
+		if err != nil {
+			return 0, fmt.Errorf("bad number %q", num)
+		}
+
+		fm := uint64(math.Round(f * float64(unit)))
+		if fm > limit-m {
+			return 0, fmt.Errorf("overflow")
+		}
+		m += fm
+	}
+
+	// A magnitude of 1<<63 converts to math.MinInt64, which is its own
+	// negation.
+	d := time.Duration(m)
+	if neg {
+		d = -d
+	}
+
+	return d, nil
+}
+
+// addChecked returns a+b and whether that sum is free of overflow.
+func addChecked[T ~int | ~int64](a, b T) (T, bool) {
+	if c := a + b; (c > a) == (b > 0) {
+		return c, true
+	}
+	return 0, false
+}
+
+// mulChecked returns a*b and whether that product is free of overflow.
+func mulChecked[T ~int | ~int64](a, b T) (T, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	// Dividing back detects every overflow except -1 times the most
+	// negative value, which is its own negation.
+	if c := a * b; c/b == a && !(a < 0 && b < 0 && c < 0) {
+		return c, true
+	}
+	return 0, false
+}
+
+// isoDuration holds the components of an ISO 8601 duration.
+type isoDuration struct {
+	years, months, weeks, days int
+	clock                      time.Duration
+}
+
+// parseISODuration parses the portion of an ISO 8601 duration following its
+// leading "P". Date components must be integers while time components may
+// have a fractional part (with either a '.' or ',' as its separator). If neg
+// is true, each component is negated (and the time components are summed as
+// negative values).
+func parseISODuration(s string, neg bool) (isoDuration, error) {
+	var id isoDuration
+
+	date, clock, hasT := strings.Cut(s, "T")
+	if s == "" || (hasT && clock == "") {
+		return id, fmt.Errorf("missing components")
+	}
+
+	dateFields := []struct {
+		designator byte
+		value      *int
+	}{{'Y', &id.years}, {'M', &id.months}, {'W', &id.weeks}, {'D', &id.days}}
+
+	for date != "" {
+		i := strings.IndexFunc(date, func(r rune) bool { return !unicode.IsDigit(r) })
+		if i <= 0 {
+			return id, fmt.Errorf("bad date component %q", date)
+		}
+
+		for len(dateFields) > 0 && dateFields[0].designator != date[i] {
+			dateFields = dateFields[1:]
+		}
+
+		if len(dateFields) == 0 {
+			return id, fmt.Errorf("bad date component %q", date)
+		}
+
+		// As with scaleDuration, the magnitude is parsed unsigned so that a
+		// negated component may reach math.MinInt.
+		limit := uint64(math.MaxInt)
+		if neg {
+			limit++
+		}
+
+		u, err := strconv.ParseUint(date[:i], 10, 64)
+		if err != nil || u > limit {
+			return id, fmt.Errorf("bad date component %q", date)
+		}
+
+		n := int(u)
+		if neg {
+			n = -n
+		}
+
+		*dateFields[0].value = n
+		dateFields = dateFields[1:]
+		date = date[i+1:]
+	}
+
+	clockFields := []struct {
+		designator byte
+		unit       time.Duration
+	}{{'H', time.Hour}, {'M', time.Minute}, {'S', time.Second}}
+
+	for clock != "" {
+		i := strings.IndexFunc(clock, func(r rune) bool { return r != '.' && r != ',' && !unicode.IsDigit(r) })
+		if i <= 0 {
+			return id, fmt.Errorf("bad time component %q", clock)
+		}
+
+		for len(clockFields) > 0 && clockFields[0].designator != clock[i] {
+			clockFields = clockFields[1:]
+		}
+
+		if len(clockFields) == 0 {
+			return id, fmt.Errorf("bad time component %q", clock)
+		}
+
+		d, err := scaleDuration(strings.Replace(clock[:i], ",", ".", 1), clockFields[0].unit, neg)
+		if err != nil {
+			return id, err
+		}
+
+		var ok bool
+		if id.clock, ok = addChecked(id.clock, d); !ok {
+			return id, fmt.Errorf("overflow")
+		}
+
+		clockFields = clockFields[1:]
+		clock = clock[i+1:]
+	}
+
+	return id, nil
+}
+
+func parseISODurationOnly(s string, neg bool) (time.Duration, error) {
+	id, err := parseISODuration(s, neg)
+	if err != nil {
+		return 0, err
+	}
+
+	if id.years != 0 || id.months != 0 {
+		return 0, fmt.Errorf("years and months are not supported")
+	}
+
+	d := id.clock
+	for _, c := range []struct {
+		n    int
+		unit time.Duration
+	}{{id.weeks, Week}, {id.days, Day}} {
+		cd, ok := mulChecked(time.Duration(c.n), c.unit)
+		if ok {
+			d, ok = addChecked(d, cd)
+		}
+
+		if !ok {
+			return 0, fmt.Errorf("overflow")
+		}
+	}
+
+	return d, nil
+}
@@ -0,0 +1,78 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	cases := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"0", 0},
+		{"1h30m", 90 * time.Minute},
+		{"1.5h", 90 * time.Minute},
+		{"-2m3.5s", -(2*time.Minute + 3500*time.Millisecond)},
+		{"3d", 3 * Day},
+		{"1w2d", 9 * Day},
+		{"1 hour 30 minutes", 90 * time.Minute},
+		{"2 Days, 3 hrs and 5 secs", 2*Day + 3*time.Hour + 5*time.Second},
+		{"1.5ms", 1500 * time.Microsecond},
+		{"250µs", 250 * time.Microsecond},
+		{"P1DT2H", Day + 2*time.Hour},
+		{"P2W", 2 * Week},
+		{"PT0.5S", 500 * time.Millisecond},
+		{"PT1,5M", 90 * time.Second},
+		{"-P1D", -Day},
+		{"2562047h", 2562047 * time.Hour},
+	}
+
+	for _, tc := range cases {
+		if got, err := ParseDuration(tc.in); err != nil || got != tc.want {
+			t.Errorf("ParseDuration(%q) == (%v, %v); Wanted %v", tc.in, got, err, tc.want)
+		}
+	}
+
+	for _, in := range []string{"", "-", "d", "5", "5 fortnights", "1h-5m", "P", "P1Y", "P1M", "PT", "P1H", "PT1D", "PT1S2M", "P1.5D", "2562048h", "15251w", "-9223372036854775809ns", "-15250w1d23h47m16.854775809s"} {
+		if d, err := ParseDuration(in); !errors.Is(err, ErrBadDuration) {
+			t.Errorf("ParseDuration(%q) == (%v, %v); Wanted %v", in, d, err, ErrBadDuration)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	cases := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{90 * time.Minute, "1h30m"},
+		{9*Day + 5*time.Second, "1w2d5s"},
+		{-(Day + 1500*time.Millisecond), "-1d1.5s"},
+		{1500 * time.Microsecond, "1.5ms"},
+		{math.MinInt64, "-15250w1d23h47m16.854775808s"},
+	}
+
+	for _, tc := range cases {
+		if got := FormatDuration(tc.in); got != tc.want {
+			t.Errorf("FormatDuration(%v) == %q; Wanted %q", tc.in, got, tc.want)
+		}
+	}
+
+	for _, in := range []string{"-9223372036854775808ns", "-PT2562047H47M16.854775808S"} {
+		if got, err := ParseDuration(in); err != nil || got != math.MinInt64 {
+			t.Errorf("ParseDuration(%q) == (%v, %v); Wanted %v", in, got, err, time.Duration(math.MinInt64))
+		}
+	}
+
+	for _, d := range []time.Duration{1, time.Second, 36 * time.Hour, 3*Week + time.Nanosecond, -Week, math.MaxInt64, math.MinInt64} {
+		if got, err := ParseDuration(FormatDuration(d)); err != nil || got != d {
+			t.Errorf("ParseDuration(FormatDuration(%v)) == (%v, %v)", d, got, err)
+		}
+	}
+}
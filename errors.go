@@ -2,6 +2,11 @@
 
 package timetool
 
+import (
+	"fmt"
+	"time"
+)
+
 type Error string
 
 func (e Error) Error() string {
@@ -11,8 +16,8 @@ func (e Error) Error() string {
 //╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴
 // Ticker related errors.
 
-// ErrTickerActive is returned by NormalTicker's Err method if the ticker is
-// still active (i.e. it has not been stopped).
+// ErrTickerActive is returned by the Err method of any of this package's
+// tickers if the ticker is still active (i.e. it has not been stopped).
 const ErrTickerActive = Error("ticker is active")
 
 //╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴
@@ -49,4 +54,140 @@ const ErrNegativeDelay = Error("negative delay value; time travel not yet suppor
 // ErrZeroCoefficient is returned when a Backoff.Coefficient value is zero.
 const ErrZeroCoefficient = Error("coefficient cannot be zero")
 
+// ErrUnknownCurve is returned when a Backoff.Curve value is not one of the
+// defined BackoffCurve constants.
+const ErrUnknownCurve = Error("unknown backoff curve")
+
+// ErrAttemptFailed is the cause given when the Context passed to a failed
+// RetryErrFunc attempt is cancelled, when another attempt is to follow.
+const ErrAttemptFailed = Error("retry attempt failed")
+
+// ErrAttemptTimeout is the cause given when the Context passed to a
+// RetryErrFunc is cancelled because the attempt ran out of time.
+const ErrAttemptTimeout = Error("retry attempt timed out")
+
+// ErrRetryBudgetExceeded is returned by Backoff's Retry and RetryErr methods
+// when a retry is denied by the Backoff's RetryBudget (see WithBudget).
+const ErrRetryBudgetExceeded = Error("retry budget exceeded")
+
+// RetryError is returned by Backoff's Retry and RetryErr methods when all
+// retry attempts have been unsuccessful. It matches ErrRetriesExhausted when
+// tested with errors.Is.
+type RetryError struct {
+	// Attempts is the number of attempts made.
+	Attempts int
+
+	// Elapsed is the time spent making those attempts (including any
+	// delays between them).
+	Elapsed time.Duration
+
+	// Last is the error returned by the final attempt, if known.
+	Last error
+}
+
+func (re *RetryError) Error() string {
+	msg := fmt.Sprintf("%s after %d attempts in %v", ErrRetriesExhausted, re.Attempts, re.Elapsed)
+	if re.Last != nil {
+		msg += ": " + re.Last.Error()
+	}
+	return msg
+}
+
+// Is reports whether target is ErrRetriesExhausted.
+func (re *RetryError) Is(target error) bool {
+	return target == ErrRetriesExhausted
+}
+
+// Unwrap returns the error from the final attempt.
+func (re *RetryError) Unwrap() error {
+	return re.Last
+}
+
+// ValidationError is returned when a Backoff has an invalid field value (or
+// a related function is passed an invalid argument). It wraps one of the
+// error values above, describing the problem, such that it matches that
+// value when tested with errors.Is.
+type ValidationError struct {
+	// Field names the invalid field or argument.
+	Field string
+
+	// Value is the offending value.
+	Value any
+
+	// Err describes the problem.
+	Err error
+}
+
+func (ve *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s value %v: %v", ve.Field, ve.Value, ve.Err)
+}
+
+// Unwrap returns the error describing the problem.
+func (ve *ValidationError) Unwrap() error {
+	return ve.Err
+}
+
+//╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴
+// Schedule related errors.
+
+// ErrBadSchedule is returned (wrapped) by ParseSchedule if its spec argument
+// cannot be parsed.
+const ErrBadSchedule = Error("invalid schedule")
+
+// ErrScheduleExhausted is returned when a Schedule has no further activation
+// times.
+const ErrScheduleExhausted = Error("schedule has no further activations")
+
+// ErrNeverOpen is returned by SleepOpen and SleepUntilOpen when given a
+// Calendar that has no open hours.
+const ErrNeverOpen = Error("calendar is never open")
+
+//╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴
+// Runner related errors.
+
+// ErrRunTimeout is the cause given when the Context passed to a run is
+// cancelled because the run exceeded its time limit (see WithRunTimeout).
+const ErrRunTimeout = Error("run timed out")
+
+// PanicError is passed to RunEvery's error handler when a run panics and
+// WithPanicRecovery is in effect.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func (pe *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", pe.Value)
+}
+
+// Unwrap returns the panic value if it is an error, otherwise nil.
+func (pe *PanicError) Unwrap() error {
+	if err, ok := pe.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+//╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴
+// Parsing related errors.
+
+// ErrUnknownTimeFormat is returned (wrapped) by ParseAny when given a
+// timestamp in a format it does not recognize.
+const ErrUnknownTimeFormat = Error("unrecognized time format")
+
+// ErrBadDuration is returned (wrapped) by ParseDuration when given a string
+// it cannot parse.
+const ErrBadDuration = Error("invalid duration")
+
+// ErrBadPeriod is returned (wrapped) by ParsePeriod when given a string it
+// cannot parse.
+const ErrBadPeriod = Error("invalid period")
+
+// ErrBadInterval is returned (wrapped) by ParseInterval when given a string
+// it cannot parse.
+const ErrBadInterval = Error("invalid interval")
+
 //╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴╶╴
@@ -0,0 +1,274 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HumanizeUnit is one of the units of time used by Humanize and
+// HumanizeDuration.
+type HumanizeUnit int
+
+// The units of time used by Humanize and HumanizeDuration. Since these do not
+// take the calendar into account, a month is always 30 days and a year is
+// always 365 days.
+const (
+	HumanizeSecond HumanizeUnit = iota
+	HumanizeMinute
+	HumanizeHour
+	HumanizeDay
+	HumanizeWeek
+	HumanizeMonth
+	HumanizeYear
+)
+
+var unitDurations = [...]time.Duration{
+	HumanizeSecond: time.Second,
+	HumanizeMinute: time.Minute,
+	HumanizeHour:   time.Hour,
+	HumanizeDay:    Day,
+	HumanizeWeek:   Week,
+	HumanizeMonth:  30 * Day,
+	HumanizeYear:   365 * Day,
+}
+
+var unitNames = [...]string{"second", "minute", "hour", "day", "week", "month", "year"}
+
+// Duration returns the length of the HumanizeUnit.
+func (u HumanizeUnit) Duration() time.Duration {
+	if u < HumanizeSecond || u > HumanizeYear {
+		return 0
+	}
+	return unitDurations[u]
+}
+
+func (u HumanizeUnit) String() string {
+	if u < HumanizeSecond || u > HumanizeYear {
+		return fmt.Sprintf("HumanizeUnit(%d)", int(u))
+	}
+	return unitNames[u]
+}
+
+// HumanizeRounding determines how Humanize and HumanizeDuration handle the
+// remainder smaller than the last unit they report.
+type HumanizeRounding int
+
+const (
+	// HumanizeRoundNearest rounds to the nearest whole unit, with halves
+	// rounded away from zero. This is the default.
+	HumanizeRoundNearest HumanizeRounding = iota
+
+	// HumanizeRoundDown discards the remainder.
+	HumanizeRoundDown
+
+	// HumanizeRoundUp rounds any non-zero remainder up to a whole unit.
+	HumanizeRoundUp
+)
+
+// HumanizeLocale holds the words and phrasing used by Humanize and
+// HumanizeDuration.
+type HumanizeLocale struct {
+	// Now is used by Humanize when a time rounds to the same as now.
+	Now string
+
+	// Past and Future are format strings (for use with fmt.Sprintf) that
+	// place a humanized duration into relative phrasing -- e.g. "%s ago"
+	// and "in %s".
+	Past   string
+	Future string
+
+	// Separator joins the units of a humanized duration, except for the
+	// last two which are joined by LastSeparator.
+	Separator     string
+	LastSeparator string
+
+	// Format returns the phrase for n of the given HumanizeUnit (e.g. "1 day"
+	// or "3 weeks").
+	Format func(u HumanizeUnit, n int64) string
+}
+
+var english = HumanizeLocale{
+	Now:           "just now",
+	Past:          "%s ago",
+	Future:        "in %s",
+	Separator:     ", ",
+	LastSeparator: " and ",
+	Format: func(u HumanizeUnit, n int64) string {
+		if n == 1 {
+			return "1 " + u.String()
+		}
+		return fmt.Sprintf("%d %ss", n, u)
+	},
+}
+
+// HumanizeEnglish returns the default HumanizeLocale. Since a copy is
+// returned, it may serve as the starting point for a custom HumanizeLocale.
+func HumanizeEnglish() HumanizeLocale {
+	return english
+}
+
+// HumanizeOption is an optional setting that may be passed to Humanize or
+// HumanizeDuration.
+type HumanizeOption func(*humanizeConfig)
+
+type humanizeConfig struct {
+	precision int
+	rounding  HumanizeRounding
+	locale    HumanizeLocale
+}
+
+// HumanizeWithPrecision returns a HumanizeOption that sets the number of
+// consecutive units used to express a duration, starting from the largest
+// applicable unit; units with a value of zero are omitted. For example, 26
+// hours and 40 minutes is "1 day" with a precision of 1 (the default), "1 day
+// and 3 hours" with a precision of 2 and "1 day, 2 hours and 40 minutes" with
+// a precision of 3. Values less than 1 are treated as 1.
+func HumanizeWithPrecision(n int) HumanizeOption {
+	return func(hc *humanizeConfig) {
+		if n < 1 {
+			n = 1
+		}
+		hc.precision = n
+	}
+}
+
+// HumanizeWithRounding returns a HumanizeOption that sets the
+// HumanizeRounding applied to the smallest reported unit. The default is
+// HumanizeRoundNearest.
+func HumanizeWithRounding(r HumanizeRounding) HumanizeOption {
+	return func(hc *humanizeConfig) {
+		hc.rounding = r
+	}
+}
+
+// HumanizeWithLocale returns a HumanizeOption that sets the HumanizeLocale
+// used for wording. The default is that returned by HumanizeEnglish. Any
+// field of l left empty (or, for Format, nil) is taken from that default, so
+// even a zero HumanizeLocale may be given.
+func HumanizeWithLocale(l HumanizeLocale) HumanizeOption {
+	return func(hc *humanizeConfig) {
+		for _, f := range []struct {
+			field *string
+			def   string
+		}{
+			{&l.Now, english.Now},
+			{&l.Past, english.Past},
+			{&l.Future, english.Future},
+			{&l.Separator, english.Separator},
+			{&l.LastSeparator, english.LastSeparator},
+		} {
+			if *f.field == "" {
+				*f.field = f.def
+			}
+		}
+
+		if l.Format == nil {
+			l.Format = english.Format
+		}
+
+		hc.locale = l
+	}
+}
+
+func newHumanizeConfig(opts []HumanizeOption) *humanizeConfig {
+	hc := &humanizeConfig{precision: 1, locale: english}
+
+	for _, o := range opts {
+		o(hc)
+	}
+
+	return hc
+}
+
+// Humanize returns a description of t relative to now, such as "3 minutes
+// ago" or "in 2 days". If now is the zero Time, the current time is used.
+func Humanize(t, now time.Time, opts ...HumanizeOption) string {
+	if now.IsZero() {
+		now = timeNow()
+	}
+
+	hc := newHumanizeConfig(opts)
+
+	d := t.Sub(now)
+
+	s := hc.format(d)
+	switch {
+	case s == "":
+		return hc.locale.Now
+	case d < 0:
+		return fmt.Sprintf(hc.locale.Past, s)
+	default:
+		return fmt.Sprintf(hc.locale.Future, s)
+	}
+}
+
+// HumanizeDuration returns a description of the magnitude of d, such as
+// "2 hours" or, with a precision of 2, "2 hours and 5 minutes". A duration
+// that rounds to zero is described as zero seconds.
+func HumanizeDuration(d time.Duration, opts ...HumanizeOption) string {
+	hc := newHumanizeConfig(opts)
+
+	if s := hc.format(d); s != "" {
+		return s
+	}
+
+	return hc.locale.Format(HumanizeSecond, 0)
+}
+
+// format returns the humanized magnitude of d, or an empty string if it
+// rounds to zero.
+func (hc *humanizeConfig) format(d time.Duration) string {
+	// Work with non-positive values so math.MinInt64 needs no special case.
+	if d > 0 {
+		d = -d
+	}
+
+	top := HumanizeYear
+	for top > HumanizeSecond && d > -top.Duration() {
+		top--
+	}
+
+	smallest := top - HumanizeUnit(hc.precision-1)
+	if smallest < HumanizeSecond {
+		smallest = HumanizeSecond
+	}
+
+	// Round what remains below the smallest unit; since the units are not
+	// all multiples of one another, this is applied to the remainder of a
+	// decomposition and the result decomposed again.
+	abs := uint64(-d)
+	size := uint64(smallest.Duration())
+	for u := top; u > smallest; u-- {
+		abs %= uint64(u.Duration())
+	}
+
+	total := uint64(-d) - abs%size
+
+	switch rem := abs % size; {
+	case hc.rounding == HumanizeRoundNearest && rem >= size-size/2:
+		total += size
+	case hc.rounding == HumanizeRoundUp && rem != 0:
+		total += size
+	}
+
+	var parts []string
+	for u := HumanizeYear; u >= smallest && len(parts) < hc.precision; u-- {
+		if c := total / uint64(u.Duration()); c > 0 {
+			parts = append(parts, hc.locale.Format(u, int64(c)))
+			total -= c * uint64(u.Duration())
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return ""
+	case 1:
+		return parts[0]
+	default:
+		last := len(parts) - 1
+		return strings.Join(parts[:last], hc.locale.Separator) + hc.locale.LastSeparator + parts[last]
+	}
+}
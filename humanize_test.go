@@ -0,0 +1,85 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHumanize(t *testing.T) {
+	defer resetTimeFuncs()
+	timeNow = func() time.Time { return now }
+
+	cases := []struct {
+		t    time.Time
+		opts []HumanizeOption
+		want string
+	}{
+		{now, nil, "just now"},
+		{now.Add(-400 * time.Millisecond), nil, "just now"},
+		{now.Add(-3 * time.Minute), nil, "3 minutes ago"},
+		{now.Add(2*Day + time.Hour), nil, "in 2 days"},
+		{now.Add(-time.Second), nil, "1 second ago"},
+		{now.Add(-90 * time.Second), nil, "2 minutes ago"},
+		{now.Add(-90 * time.Second), []HumanizeOption{HumanizeWithRounding(HumanizeRoundDown)}, "1 minute ago"},
+		{now.Add(61 * time.Second), []HumanizeOption{HumanizeWithRounding(HumanizeRoundUp)}, "in 2 minutes"},
+		{now.Add(-59*time.Minute - 50*time.Second), nil, "1 hour ago"},
+		{now.Add(26*time.Hour + 40*time.Minute), []HumanizeOption{HumanizeWithPrecision(2)}, "in 1 day and 3 hours"},
+		{now.Add(26*time.Hour + 40*time.Minute), []HumanizeOption{HumanizeWithPrecision(3)}, "in 1 day, 2 hours and 40 minutes"},
+		{now.Add(-400 * Day), nil, "1 year ago"},
+		{now.Add(-400 * Day), []HumanizeOption{HumanizeWithPrecision(2)}, "1 year and 1 month ago"},
+	}
+
+	for _, tc := range cases {
+		if got := Humanize(tc.t, time.Time{}, tc.opts...); got != tc.want {
+			t.Errorf("Humanize(now%+v) == %q; Wanted %q", tc.t.Sub(now), got, tc.want)
+		}
+	}
+
+	then := now.Add(-5 * time.Hour)
+	if got, want := Humanize(now, then), "in 5 hours"; got != want {
+		t.Errorf("Humanize(%v, %v) == %q; Wanted %q", now, then, got, want)
+	}
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	cases := []struct {
+		d    time.Duration
+		opts []HumanizeOption
+		want string
+	}{
+		{0, nil, "0 seconds"},
+		{2*time.Hour + 5*time.Minute, nil, "2 hours"},
+		{2*time.Hour + 5*time.Minute, []HumanizeOption{HumanizeWithPrecision(2)}, "2 hours and 5 minutes"},
+		{-3 * Week, nil, "3 weeks"},
+		{math.MinInt64, []HumanizeOption{HumanizeWithPrecision(7)}, "292 years, 5 months, 3 weeks, 23 hours, 47 minutes and 17 seconds"},
+	}
+
+	for _, tc := range cases {
+		if got := HumanizeDuration(tc.d, tc.opts...); got != tc.want {
+			t.Errorf("HumanizeDuration(%v) == %q; Wanted %q", tc.d, got, tc.want)
+		}
+	}
+
+	pirate := HumanizeEnglish()
+	pirate.Past = "%s back, matey"
+	pirate.Format = func(u HumanizeUnit, n int64) string {
+		return fmt.Sprintf("%d %s(s)", n, u)
+	}
+
+	if got, want := Humanize(now.Add(-2*Day), now, HumanizeWithLocale(pirate)), "2 day(s) back, matey"; got != want {
+		t.Errorf("Humanize with custom locale == %q; Wanted %q", got, want)
+	}
+
+	partial := HumanizeLocale{Past: "%s back"}
+	if got, want := Humanize(now.Add(-2*Day), now, HumanizeWithLocale(partial)), "2 days back"; got != want {
+		t.Errorf("Humanize with partial locale == %q; Wanted %q", got, want)
+	}
+
+	if got, want := HumanizeDuration(0, HumanizeWithLocale(HumanizeLocale{})), "0 seconds"; got != want {
+		t.Errorf("HumanizeDuration with zero locale == %q; Wanted %q", got, want)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseOption is an optional setting that may be passed to ParseAny.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	loc     *time.Location
+	refYear int
+	layouts []string
+}
+
+// ParseWithLocation returns a ParseOption that sets the Location used to
+// interpret timestamps lacking zone information. The default is UTC.
+func ParseWithLocation(loc *time.Location) ParseOption {
+	return func(pc *parseConfig) {
+		if loc != nil {
+			pc.loc = loc
+		}
+	}
+}
+
+// ParseWithReferenceYear returns a ParseOption that sets the year assigned to
+// timestamps that lack one (e.g. those from syslog). By default, the current
+// year is used unless that would place the timestamp more than a day in the
+// future, in which case the previous year is used. A timestamp falling on
+// February 29 is instead assigned the nearest leap year not after that year.
+func ParseWithReferenceYear(year int) ParseOption {
+	return func(pc *parseConfig) {
+		pc.refYear = year
+	}
+}
+
+// ParseWithLayouts returns a ParseOption adding layouts (as accepted by
+// time.Parse) to be tried, in the order given, ahead of the default layouts.
+func ParseWithLayouts(layouts ...string) ParseOption {
+	return func(pc *parseConfig) {
+		pc.layouts = append(pc.layouts, layouts...)
+	}
+}
+
+var defaultLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"20060102", // ISO 8601 basic format; tried ahead of Epoch timestamps
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.RFC822Z,
+	time.RFC822,
+	time.UnixDate,
+	time.RubyDate,
+	time.ANSIC,
+	"02/Jan/2006:15:04:05 -0700", // Apache Common Log Format
+	time.StampNano,
+	time.Stamp, // syslog
+}
+
+// DefaultLayouts returns the layouts tried by ParseAny, in order.
+func DefaultLayouts() []string {
+	return append([]string(nil), defaultLayouts...)
+}
+
+var (
+	epochRE   = regexp.MustCompile(`^[-+]?\d+(\.\d+)?$`)
+	isoWeekRE = regexp.MustCompile(`^(\d{4})-?W(\d{2})(?:-?([1-7]))?$`)
+)
+
+// ParseAny parses s as a timestamp in any of a number of common formats. In
+// addition to any layouts added using ParseWithLayouts, it tries each of the
+// layouts returned by DefaultLayouts (which include RFC 3339, RFC 1123,
+// Apache's Common Log Format, the syslog format and ISO 8601 basic dates such
+// as "20100714"), followed by ISO 8601 week dates (e.g. "2024-W05-3", or
+// "2024-W05" for that week's Monday) and, lastly, numeric Epoch based
+// timestamps, whose units are determined by DetectEpochUnit.
+//
+// Timestamps lacking zone information are interpreted in UTC, unless
+// another Location is given using ParseWithLocation. Those lacking a year are
+// assigned one as described for ParseWithReferenceYear.
+//
+// An error wrapping ErrUnknownTimeFormat is returned if s matches none of
+// the formats above.
+func ParseAny(s string, opts ...ParseOption) (time.Time, error) {
+	pc := &parseConfig{loc: time.UTC}
+	for _, o := range opts {
+		o(pc)
+	}
+
+	s = strings.TrimSpace(s)
+
+	for _, l := range append(pc.layouts, defaultLayouts...) {
+		if t, err := time.ParseInLocation(l, s, pc.loc); err == nil {
+			if !hasYear(l) {
+				t = pc.withYear(t)
+			}
+			return t, nil
+		}
+	}
+
+	if t, ok := parseISOWeek(s, pc.loc); ok {
+		return t, nil
+	}
+
+	if epochRE.MatchString(s) {
+		if t, ok := parseEpoch(s); ok {
+			return t.In(pc.loc), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q", ErrUnknownTimeFormat, s)
+}
+
+// hasYear reports whether layout l includes a year, in either its two or
+// four digit form (both of which contain "06").
+func hasYear(l string) bool {
+	return strings.Contains(l, "06")
+}
+
+func (pc *parseConfig) withYear(t time.Time) time.Time {
+	year := pc.refYear
+
+	if year == 0 {
+		now := timeNow().In(pc.loc)
+		if year = now.Year(); t.AddDate(year, 0, 0).After(now.Add(24 * time.Hour)) {
+			year--
+		}
+	}
+
+	// Year 0 is a leap year, so t may be February 29; moving that to a
+	// common year would roll it over into March.
+	if t.Month() == time.February && t.Day() == 29 {
+		for !isLeap(year) {
+			year--
+		}
+	}
+
+	return t.AddDate(year, 0, 0)
+}
+
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+func parseISOWeek(s string, loc *time.Location) (time.Time, bool) {
+	m := isoWeekRE.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}, false
+	}
+
+	year, _ := strconv.Atoi(m[1])
+	week, _ := strconv.Atoi(m[2])
+
+	if week < 1 || week > 53 {
+		return time.Time{}, false
+	}
+
+	day := 1
+	if m[3] != "" {
+		day, _ = strconv.Atoi(m[3])
+	}
+
+	// Week 1 is the week containing January 4th.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	mon := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	t := mon.AddDate(0, 0, (week-1)*7+day-1)
+
+	// Only some years have a week 53.
+	if y, w := t.ISOWeek(); y != year || w != week {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+func parseEpoch(s string) (time.Time, bool) {
+	if strings.Contains(s, ".") {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		u, ok := DetectEpochUnit(v)
+		return FromEpoch(v, u), ok
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	u, ok := DetectEpochUnit(v)
+	return FromEpoch(v, u), ok
+}
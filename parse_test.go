@@ -0,0 +1,61 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseAny(t *testing.T) {
+	defer resetTimeFuncs()
+	timeNow = func() time.Time { return now } // 2010-07-14 18:00 UTC
+
+	nyc := mustLoadLocation(t, "America/New_York")
+	want := time.Date(2010, 7, 14, 18, 12, 36, 0, time.UTC)
+
+	cases := []struct {
+		in   string
+		opts []ParseOption
+		want time.Time
+	}{
+		{"2010-07-14T18:12:36Z", nil, want},
+		{"2010-07-14T11:12:36.512-07:00", nil, want.Add(512 * time.Millisecond)},
+		{"2010-07-14T18:12:36", nil, want},
+		{"2010-07-14 18:12:36", []ParseOption{ParseWithLocation(nyc)}, time.Date(2010, 7, 14, 18, 12, 36, 0, nyc)},
+		{"2010-07-14", nil, time.Date(2010, 7, 14, 0, 0, 0, 0, time.UTC)},
+		{"20100714", nil, time.Date(2010, 7, 14, 0, 0, 0, 0, time.UTC)},
+		{"0000-01-01T00:00:00Z", nil, time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Wed, 14 Jul 2010 18:12:36 +0000", nil, want},
+		{"Wed Jul 14 18:12:36 2010", nil, want},
+		{"14/Jul/2010:11:12:36 -0700", nil, want},
+		{"Jul 14 18:12:36", nil, want},
+		{"Dec 31 23:59:59", nil, time.Date(2009, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{"Dec 31 23:59:59", []ParseOption{ParseWithReferenceYear(2012)}, time.Date(2012, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{"Feb 29 12:00:00", nil, time.Date(2008, 2, 29, 12, 0, 0, 0, time.UTC)},
+		{"Feb 29 12:00:00", []ParseOption{ParseWithReferenceYear(2013)}, time.Date(2012, 2, 29, 12, 0, 0, 0, time.UTC)},
+		{"2010-W28-3", nil, time.Date(2010, 7, 14, 0, 0, 0, 0, time.UTC)},
+		{"2010W28", nil, time.Date(2010, 7, 12, 0, 0, 0, 0, time.UTC)},
+		{"2009-W53-5", nil, time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"1279131156", nil, want},
+		{"1279131156512", nil, want.Add(512 * time.Millisecond)},
+		{"1279131156.5", nil, want.Add(500 * time.Millisecond)},
+		{"14.07.2010 18:12", []ParseOption{ParseWithLayouts("02.01.2006 15:04")}, want.Truncate(time.Minute)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseAny(tc.in, tc.opts...)
+			if err != nil || !got.Equal(tc.want) {
+				t.Errorf("ParseAny(%q) == (%v, %v); Wanted %v", tc.in, got, err, tc.want)
+			}
+		})
+	}
+
+	for _, in := range []string{"", "yesterday", "2010-W54-1", "2010-W53-1", "-5000000000000000000"} {
+		if _, err := ParseAny(in); !errors.Is(err, ErrUnknownTimeFormat) {
+			t.Errorf("ParseAny(%q) == %v; Wanted %v", in, err, ErrUnknownTimeFormat)
+		}
+	}
+}
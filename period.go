@@ -0,0 +1,416 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Period is a calendar aware span of time, as expressed by an ISO 8601
+// duration such as "P1Y2M10DT2H30M". Unlike a time.Duration, the length of a
+// Period depends upon the time to which it is added: a month may have 28 to
+// 31 days and, when daylight saving time begins or ends, a day may have 23 or
+// 25 hours.
+type Period struct {
+	Years    int
+	Months   int
+	Days     int
+	Duration time.Duration
+}
+
+// ParsePeriod parses an ISO 8601 duration, optionally preceded by a sign
+// (e.g. "P1Y2M", "P3W" or "-P1DT12H"). Weeks are converted to 7 Days. Only
+// the time components (hours, minutes and seconds) may be fractional.
+//
+// An error wrapping ErrBadPeriod is returned if s cannot be parsed.
+func ParsePeriod(s string) (Period, error) {
+	in := s
+
+	neg := false
+	if in != "" && (in[0] == '-' || in[0] == '+') {
+		neg = in[0] == '-'
+		in = in[1:]
+	}
+
+	if !strings.HasPrefix(in, "P") {
+		return Period{}, fmt.Errorf("%w %q: missing leading 'P'", ErrBadPeriod, s)
+	}
+
+	id, err := parseISODuration(in[1:], neg)
+	if err != nil {
+		return Period{}, fmt.Errorf("%w %q: %v", ErrBadPeriod, s, err)
+	}
+
+	days, ok := mulChecked(id.weeks, 7)
+	if ok {
+		days, ok = addChecked(days, id.days)
+	}
+
+	if !ok {
+		return Period{}, fmt.Errorf("%w %q: too many days", ErrBadPeriod, s)
+	}
+
+	return Period{
+		Years:    id.years,
+		Months:   id.months,
+		Days:     days,
+		Duration: id.clock,
+	}, nil
+}
+
+// MustParsePeriod is a wrapper around ParsePeriod that will panic if an error
+// is returned.
+func MustParsePeriod(s string) Period {
+	p, err := ParsePeriod(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// IsZero reports whether p is the zero Period.
+func (p Period) IsZero() bool {
+	return p == Period{}
+}
+
+// Neg returns p with each of its components negated.
+func (p Period) Neg() Period {
+	return Period{-p.Years, -p.Months, -p.Days, -p.Duration}
+}
+
+// Scale returns p with each of its components multiplied by n. If any of
+// those products would overflow, the zero Period and false are returned.
+func (p Period) Scale(n int) (Period, bool) {
+	years, ok1 := mulChecked(p.Years, n)
+	months, ok2 := mulChecked(p.Months, n)
+	days, ok3 := mulChecked(p.Days, n)
+	d, ok4 := mulChecked(p.Duration, time.Duration(n))
+
+	if !(ok1 && ok2 && ok3 && ok4) {
+		return Period{}, false
+	}
+
+	return Period{years, months, days, d}, true
+}
+
+// AddTo returns the time t+p. Years and Months are added first, after which
+// the day of the month is clamped to the last day of the resulting month (so
+// Jan 31 plus one month is Feb 28 or 29). Days are then added keeping the
+// same wall clock time in t's Location, regardless of any daylight saving
+// time transitions. Finally, Duration is added as an absolute amount of
+// elapsed time.
+func (p Period) AddTo(t time.Time) time.Time {
+	if p.Years != 0 || p.Months != 0 || p.Days != 0 {
+		year, month, day := t.Date()
+		hour, min, sec := t.Clock()
+
+		// Normalize the target month before clamping the day
+		first := time.Date(year+p.Years, month+time.Month(p.Months), 1, 0, 0, 0, 0, time.UTC)
+		if last := daysIn(first.Year(), first.Month()); day > last {
+			day = last
+		}
+
+		t = time.Date(first.Year(), first.Month(), day+p.Days, hour, min, sec, t.Nanosecond(), t.Location())
+	}
+
+	return t.Add(p.Duration)
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// String returns p as an ISO 8601 duration (e.g. "P1Y2M10DT2H30M"). A zero
+// Period is "PT0S". If no component of p is positive, the result carries a
+// leading minus sign (e.g. "-P1D"). Since ISO 8601 has no notation for
+// components of differing signs, such a Period is formatted with a sign on
+// each negative component and cannot be parsed by ParsePeriod.
+func (p Period) String() string {
+	if p.IsZero() {
+		return "PT0S"
+	}
+
+	var sb strings.Builder
+
+	neg := p.Years <= 0 && p.Months <= 0 && p.Days <= 0 && p.Duration <= 0
+	if neg {
+		sb.WriteByte('-')
+	}
+
+	// Each component is written as a sign (unless the whole Period is
+	// negative) and an unsigned magnitude, so that the most negative values
+	// need no special case.
+	magnitude := func(n int64) uint64 {
+		u := uint64(n)
+		if n < 0 {
+			if !neg {
+				sb.WriteByte('-')
+			}
+			u = -u
+		}
+		return u
+	}
+
+	sb.WriteByte('P')
+
+	for _, c := range []struct {
+		n          int
+		designator byte
+	}{{p.Years, 'Y'}, {p.Months, 'M'}, {p.Days, 'D'}} {
+		if c.n != 0 {
+			sb.WriteString(strconv.FormatUint(magnitude(int64(c.n)), 10))
+			sb.WriteByte(c.designator)
+		}
+	}
+
+	if p.Duration == 0 {
+		return sb.String()
+	}
+
+	sb.WriteByte('T')
+
+	u := magnitude(int64(p.Duration))
+
+	if h := u / uint64(time.Hour); h > 0 {
+		fmt.Fprintf(&sb, "%dH", h)
+		u -= h * uint64(time.Hour)
+	}
+
+	if m := u / uint64(time.Minute); m > 0 {
+		fmt.Fprintf(&sb, "%dM", m)
+		u -= m * uint64(time.Minute)
+	}
+
+	if u > 0 {
+		s, ns := u/uint64(time.Second), u%uint64(time.Second)
+		sb.WriteString(strconv.FormatUint(s, 10))
+		if ns > 0 {
+			sb.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", ns), "0"))
+		}
+		sb.WriteByte('S')
+	}
+
+	return sb.String()
+}
+
+// Interval is an ISO 8601 time interval, optionally recurring.
+type Interval struct {
+	// Start and End delimit the (first) occurrence of the interval.
+	Start time.Time
+	End   time.Time
+
+	// Period is the Period from which Start or End was derived, if the
+	// interval was expressed as either "start/period" or "period/end". It is
+	// zero if the interval was given as "start/end".
+	Period Period
+
+	// Repeats is the number of occurrences of a recurring interval, or -1
+	// if it recurs without limit. A value of 0 indicates an interval that
+	// does not recur (i.e. it occurs exactly once).
+	Repeats int
+
+	// endAnchored is true for an interval expressed as "period/end", whose
+	// occurrences lead up to (instead of away from) End.
+	endAnchored bool
+}
+
+// ParseInterval parses an ISO 8601 time interval in one of the forms:
+//
+//	<start>/<end>
+//	<start>/<period>
+//	<period>/<end>
+//
+// each of which may be prefixed with a repeat count as "Rn/" (for n
+// occurrences) or "R/" (recurring without limit). The start and end times are
+// parsed by ParseAny, to which opts are passed, and so may be given in any of
+// its supported layouts -- including those, such as Apache's Common Log
+// Format, that contain a '/' of their own; the abbreviated end times allowed
+// by ISO 8601 (e.g. "2024-02-01/15") are not supported. Periods are parsed by
+// ParsePeriod.
+//
+// For "start/period", End is computed as period.AddTo(Start); for
+// "period/end", Start is computed as period.Neg().AddTo(End).
+//
+// An error wrapping ErrBadInterval is returned if s cannot be parsed.
+func ParseInterval(s string, opts ...ParseOption) (Interval, error) {
+	var iv Interval
+
+	rest := s
+	if r, tail, ok := strings.Cut(s, "/"); ok && strings.HasPrefix(r, "R") {
+		iv.Repeats = -1
+		if r = r[1:]; r != "" {
+			n, err := strconv.Atoi(r)
+			if err != nil || n < 0 {
+				return Interval{}, fmt.Errorf("%w %q: bad repeat count %q", ErrBadInterval, s, r)
+			}
+			iv.Repeats = n
+		}
+		rest = tail
+	}
+
+	// Since some of ParseAny's layouts contain a '/' of their own, each is
+	// tried in turn as the separator until one divides rest into two valid
+	// components.
+	var err error
+	for i := 0; i < len(rest); i++ {
+		if rest[i] != '/' {
+			continue
+		}
+
+		perr := iv.parseEnds(rest[:i], rest[i+1:], opts)
+		if perr == nil {
+			if iv.End.Before(iv.Start) {
+				return Interval{}, fmt.Errorf("%w %q: end precedes start", ErrBadInterval, s)
+			}
+			return iv, nil
+		}
+
+		if err == nil {
+			err = perr
+		}
+	}
+
+	if err == nil {
+		err = fmt.Errorf("missing '/' separator")
+	}
+
+	return Interval{}, fmt.Errorf("%w %q: %v", ErrBadInterval, s, err)
+}
+
+// parseEnds sets iv's Start, End and Period from the two components of an
+// interval (other than its repeat count).
+func (iv *Interval) parseEnds(start, end string, opts []ParseOption) error {
+	*iv = Interval{Repeats: iv.Repeats}
+
+	var err error
+
+	switch startP, endP := strings.HasPrefix(start, "P"), strings.HasPrefix(end, "P"); {
+	case startP && endP:
+		return fmt.Errorf("missing start or end time")
+
+	case endP:
+		if iv.Start, err = ParseAny(start, opts...); err == nil {
+			if iv.Period, err = ParsePeriod(end); err == nil {
+				iv.End = iv.Period.AddTo(iv.Start)
+			}
+		}
+
+	case startP:
+		if iv.Period, err = ParsePeriod(start); err == nil {
+			if iv.End, err = ParseAny(end, opts...); err == nil {
+				iv.Start = iv.Period.Neg().AddTo(iv.End)
+				iv.endAnchored = true
+			}
+		}
+
+	default:
+		if iv.Start, err = ParseAny(start, opts...); err == nil {
+			iv.End, err = ParseAny(end, opts...)
+		}
+	}
+
+	return err
+}
+
+// Duration returns the length of the interval's first occurrence.
+func (iv Interval) Duration() time.Duration {
+	return iv.End.Sub(iv.Start)
+}
+
+// Next returns the start of the first occurrence of iv that begins after the
+// given time, or the zero Time if there is none. This allows a recurring
+// Interval to be used as a Schedule.
+//
+// Occurrences are spaced by the interval's Period (or, if it has none, by its
+// Duration) and follow one another from Start. However, the occurrences of a
+// recurring interval given as "period/end" lead up to its End, with the last
+// beginning at Start; since such an interval with no limit has no first
+// occurrence, Next always returns the zero Time for it.
+func (iv Interval) Next(after time.Time) time.Time {
+	step := iv.Period
+	if step.IsZero() {
+		step = Period{Duration: iv.Duration()}
+	}
+
+	count := iv.Repeats
+	switch {
+	case count == 0 || !step.AddTo(iv.Start).After(iv.Start):
+		count = 1
+	case count < 0 && iv.endAnchored:
+		return time.Time{}
+	}
+
+	// at returns the start of occurrence k, or false if it lies too far
+	// away to be computed.
+	at := func(k int) (time.Time, bool) {
+		if iv.endAnchored {
+			k -= count - 1
+		}
+		sp, ok := step.Scale(k)
+		return sp.AddTo(iv.Start), ok
+	}
+
+	first, ok := at(0)
+	if !ok {
+		return time.Time{}
+	}
+
+	// Skip ahead to (just before) the first candidate occurrence; since
+	// the length of a Period may vary, the estimate is corrected as needed.
+	k := 0
+	if after.After(first) {
+		if approx := step.AddTo(first).Sub(first); approx > 0 {
+			k = int(after.Sub(first) / approx)
+		}
+	}
+
+	if count > 0 && k > count {
+		k = count
+	}
+
+	for ; k > 0; k-- {
+		if t, ok := at(k); ok && !t.After(after) {
+			break
+		}
+	}
+
+	for ; count < 0 || k < count; k++ {
+		t, ok := at(k)
+		if !ok {
+			break
+		}
+
+		if t.After(after) {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// String returns iv as an ISO 8601 interval, using RFC 3339 timestamps and
+// the form from which it was parsed.
+func (iv Interval) String() string {
+	var sb strings.Builder
+
+	switch {
+	case iv.Repeats < 0:
+		sb.WriteString("R/")
+	case iv.Repeats > 0:
+		fmt.Fprintf(&sb, "R%d/", iv.Repeats)
+	}
+
+	switch {
+	case iv.Period.IsZero():
+		fmt.Fprintf(&sb, "%s/%s", iv.Start.Format(time.RFC3339Nano), iv.End.Format(time.RFC3339Nano))
+	case iv.endAnchored:
+		fmt.Fprintf(&sb, "%s/%s", iv.Period, iv.End.Format(time.RFC3339Nano))
+	default:
+		fmt.Fprintf(&sb, "%s/%s", iv.Start.Format(time.RFC3339Nano), iv.Period)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,189 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParsePeriod(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Period
+		str  string
+	}{
+		{"P1Y2M10DT2H30M", Period{1, 2, 10, 2*time.Hour + 30*time.Minute}, "P1Y2M10DT2H30M"},
+		{"P3W", Period{Days: 21}, "P21D"},
+		{"-P1DT12H", Period{Days: -1, Duration: -12 * time.Hour}, "-P1DT12H"},
+		{"PT0.25S", Period{Duration: 250 * time.Millisecond}, "PT0.25S"},
+		{"PT0S", Period{}, "PT0S"},
+		{"P1M", Period{Months: 1}, "P1M"},
+	}
+
+	for _, tc := range cases {
+		got, err := ParsePeriod(tc.in)
+		if err != nil || got != tc.want {
+			t.Errorf("ParsePeriod(%q) == (%v, %v); Wanted %v", tc.in, got, err, tc.want)
+		}
+
+		if s := got.String(); s != tc.str {
+			t.Errorf("%#v.String() == %q; Wanted %q", got, s, tc.str)
+		}
+	}
+
+	for _, p := range []Period{
+		{Duration: math.MinInt64},
+		{Duration: math.MaxInt64},
+		{Days: math.MinInt},
+		{Years: math.MaxInt, Months: 1},
+		{Years: -1, Days: -2, Duration: -time.Second},
+	} {
+		if got, err := ParsePeriod(p.String()); err != nil || got != p {
+			t.Errorf("ParsePeriod(%q) == (%#v, %v); Wanted %#v", p.String(), got, err, p)
+		}
+	}
+
+	if got, want := (Period{Duration: math.MinInt64}).String(), "-PT2562047H47M16.854775808S"; got != want {
+		t.Errorf("Period{Duration: math.MinInt64}.String() == %q; Wanted %q", got, want)
+	}
+
+	for _, in := range []string{"", "1Y", "P", "PT", "P1.5M", "P1D2Y", "1d", "P1317624576693539402W", "P9223372036854775807W1D", "P9223372036854775808D"} {
+		if _, err := ParsePeriod(in); !errors.Is(err, ErrBadPeriod) {
+			t.Errorf("ParsePeriod(%q) == %v; Wanted %v", in, err, ErrBadPeriod)
+		}
+	}
+}
+
+func TestPeriodScale(t *testing.T) {
+	p := Period{1, 2, 3, time.Second}
+
+	if got, ok := p.Scale(-2); !ok || got != (Period{-2, -4, -6, -2 * time.Second}) {
+		t.Errorf("%v.Scale(-2) == (%v, %t); Wanted (%v, true)", p, got, ok, Period{-2, -4, -6, -2 * time.Second})
+	}
+
+	for _, p := range []Period{{Days: math.MaxInt/2 + 1}, {Duration: math.MinInt64}} {
+		if got, ok := p.Scale(2); ok {
+			t.Errorf("%v.Scale(2) == (%v, true); Wanted false", p, got)
+		}
+	}
+}
+
+func TestPeriodAddTo(t *testing.T) {
+	la := mustLoadLocation(t, "America/Los_Angeles")
+
+	cases := []struct {
+		t    time.Time
+		p    string
+		want time.Time
+	}{
+		{time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC), "P1M", time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC), "P1M", time.Date(2023, 2, 28, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), "P1Y", time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC), "P1M1D", time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC), "-P1M", time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 11, 30, 9, 0, 0, 0, time.UTC), "P3M", time.Date(2025, 2, 28, 9, 0, 0, 0, time.UTC)},
+		// Days keep the wall clock across a DST transition; hours do not.
+		{time.Date(2024, 3, 9, 12, 0, 0, 0, la), "P1D", time.Date(2024, 3, 10, 12, 0, 0, 0, la)},
+		{time.Date(2024, 3, 9, 12, 0, 0, 0, la), "PT24H", time.Date(2024, 3, 10, 13, 0, 0, 0, la)},
+	}
+
+	for _, tc := range cases {
+		if got := MustParsePeriod(tc.p).AddTo(tc.t); !got.Equal(tc.want) {
+			t.Errorf("%s.AddTo(%v) == %v; Wanted %v", tc.p, tc.t, got, tc.want)
+		}
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	start := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		in      string
+		start   time.Time
+		end     time.Time
+		repeats int
+	}{
+		{"2024-01-31T09:00:00Z/2024-02-01T09:00:00Z", start, start.Add(Day), 0},
+		{"2024-01-31T09:00:00Z/P1M", start, time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), 0},
+		{"P1DT1H/2024-02-01T10:00:00Z", start, start.Add(Day + time.Hour), 0},
+		{"R5/2024-01-31T09:00:00Z/PT1H", start, start.Add(time.Hour), 5},
+		{"R/2024-01-31T09:00:00Z/PT1H", start, start.Add(time.Hour), -1},
+	}
+
+	for _, tc := range cases {
+		iv, err := ParseInterval(tc.in)
+		if err != nil || !iv.Start.Equal(tc.start) || !iv.End.Equal(tc.end) || iv.Repeats != tc.repeats {
+			t.Errorf("ParseInterval(%q) == (%v, %v); Wanted %v/%v (R%d)", tc.in, iv, err, tc.start, tc.end, tc.repeats)
+			continue
+		}
+
+		if s := iv.String(); s != tc.in {
+			t.Errorf("ParseInterval(%q).String() == %q", tc.in, s)
+		}
+	}
+
+	// Apache's Common Log Format contains a '/' of its own.
+	clf := time.Date(2000, 10, 10, 13, 55, 36, 0, time.FixedZone("", -7*60*60))
+
+	if iv, err := ParseInterval("10/Oct/2000:13:55:36 -0700/P1D"); err != nil || !iv.Start.Equal(clf) || !iv.End.Equal(clf.AddDate(0, 0, 1)) {
+		t.Errorf("ParseInterval() of CLF start == (%v, %v); Wanted %v/%v", iv, err, clf, clf.AddDate(0, 0, 1))
+	}
+
+	if iv, err := ParseInterval("R2/P1D/10/Oct/2000:13:55:36 -0700"); err != nil || !iv.Start.Equal(clf.AddDate(0, 0, -1)) || !iv.End.Equal(clf) || iv.Repeats != 2 {
+		t.Errorf("ParseInterval() of CLF end == (%v, %v); Wanted %v/%v (R2)", iv, err, clf.AddDate(0, 0, -1), clf)
+	}
+
+	for _, in := range []string{"", "2024-01-31", "P1D/P2D", "Rx/2024-01-31/P1D", "2024-02-01/2024-01-31", "2024-01-31/P1D/P1D", "2024-01-31/1Q"} {
+		if _, err := ParseInterval(in); !errors.Is(err, ErrBadInterval) {
+			t.Errorf("ParseInterval(%q) == %v; Wanted %v", in, err, ErrBadInterval)
+		}
+	}
+}
+
+func TestIntervalNext(t *testing.T) {
+	ts := func(s string) time.Time {
+		t.Helper()
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	cases := []struct {
+		iv    string
+		after string
+		want  string
+	}{
+		{"2024-01-31T09:00:00Z/P1D", "2024-01-01T00:00:00Z", "2024-01-31T09:00:00Z"},
+		{"2024-01-31T09:00:00Z/P1D", "2024-01-31T09:00:00Z", ""},
+		{"R3/2024-01-31T09:00:00Z/P1M", "2024-01-31T09:00:00Z", "2024-02-29T09:00:00Z"},
+		{"R3/2024-01-31T09:00:00Z/P1M", "2024-02-29T09:00:00Z", "2024-03-31T09:00:00Z"},
+		{"R3/2024-01-31T09:00:00Z/P1M", "2024-03-31T09:00:00Z", ""},
+		{"R/2024-01-31T09:00:00Z/P1M", "2032-06-01T00:00:00Z", "2032-06-30T09:00:00Z"},
+		{"R/2024-01-01T00:00:00Z/2024-01-01T00:15:00Z", "2024-01-03T10:07:00Z", "2024-01-03T10:15:00Z"},
+		{"R3/P1D/2024-01-10T00:00:00Z", "2024-01-01T00:00:00Z", "2024-01-07T00:00:00Z"},
+		{"R3/P1D/2024-01-10T00:00:00Z", "2024-01-08T00:00:00Z", "2024-01-09T00:00:00Z"},
+		{"R3/P1D/2024-01-10T00:00:00Z", "2024-01-09T00:00:00Z", ""},
+		{"R/P1D/2024-01-10T00:00:00Z", "2024-01-01T00:00:00Z", ""},
+	}
+
+	for _, tc := range cases {
+		iv, err := ParseInterval(tc.iv)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var want time.Time
+		if tc.want != "" {
+			want = ts(tc.want)
+		}
+
+		if got := iv.Next(ts(tc.after)); !got.Equal(want) {
+			t.Errorf("%s.Next(%s) == %v; Wanted %v", tc.iv, tc.after, got, want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket rate limiter. Its bucket holds up to burst
+// tokens, each event consumes one, and tokens are replenished at a rate of
+// one per interval. A full bucket therefore allows a burst of events after
+// which the sustained rate is one event per interval.
+//
+// Rather than counting tokens, a RateLimiter tracks the time at which its
+// bucket will next be full, which is advanced by interval for each event.
+//
+// A RateLimiter is safe for concurrent use.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    int
+	full     time.Time
+}
+
+// NewRateLimiter returns a new RateLimiter, with a full bucket, that allows
+// one event per interval with bursts of up to burst events. A burst less than
+// 1 is treated as 1. If interval is not positive, all events are allowed.
+func NewRateLimiter(interval time.Duration, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{interval: interval, burst: burst}
+}
+
+// Allow reports whether an event may happen now, consuming a token if so.
+func (rl *RateLimiter) Allow() bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := timeNow()
+
+	if rl.available(now).After(now) {
+		return false
+	}
+
+	rl.take(now)
+	return true
+}
+
+// Reserve consumes a token, whether or not one is currently available, and
+// returns a Reservation indicating when the event it represents may happen.
+// The caller should wait until then or else cancel the Reservation.
+func (rl *RateLimiter) Reserve() *Reservation {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := timeNow()
+	at := latest(rl.available(now), now)
+
+	rl.take(now)
+
+	return &Reservation{rl: rl, at: at}
+}
+
+// Wait blocks until an event may happen, consuming a token, while honoring
+// ctx as with Sleep. If ctx is done first, the token is returned and the
+// Context's error is returned.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	r := rl.Reserve()
+
+	if err := Sleep(ctx, r.Delay()); err != nil {
+		r.Cancel()
+		return err
+	}
+
+	return nil
+}
+
+// available returns the time at which the next token will be available,
+// which may be before now. Must be called with rl.mu held.
+func (rl *RateLimiter) available(now time.Time) time.Time {
+	if rl.interval <= 0 {
+		return time.Time{}
+	}
+
+	return latest(rl.full, now).Add(-time.Duration(rl.burst-1) * rl.interval)
+}
+
+// take consumes a token. Must be called with rl.mu held.
+func (rl *RateLimiter) take(now time.Time) {
+	if rl.interval > 0 {
+		rl.full = latest(rl.full, now).Add(rl.interval)
+	}
+}
+
+// Reservation holds a token consumed by RateLimiter.Reserve.
+type Reservation struct {
+	rl       *RateLimiter
+	at       time.Time
+	canceled bool
+}
+
+// Time returns the time at which the reserved event may happen.
+func (r *Reservation) Time() time.Time {
+	return r.at
+}
+
+// Delay returns how long the caller must wait before the reserved event may
+// happen, or zero if it may happen now.
+func (r *Reservation) Delay() time.Duration {
+	if d := r.at.Sub(timeNow()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Cancel returns the Reservation's token to its RateLimiter, provided the
+// reserved time has not yet arrived; otherwise, the event is assumed to have
+// happened and Cancel does nothing. Calling Cancel more than once has no
+// further effect.
+func (r *Reservation) Cancel() {
+	r.rl.mu.Lock()
+	defer r.rl.mu.Unlock()
+
+	if r.canceled || r.rl.interval <= 0 || !r.at.After(timeNow()) {
+		return
+	}
+
+	r.canceled = true
+	r.rl.full = r.rl.full.Add(-r.rl.interval)
+}
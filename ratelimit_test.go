@@ -0,0 +1,159 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	defer resetTimeFuncs()
+
+	clock := now
+	timeNow = func() time.Time { return clock }
+
+	rl := NewRateLimiter(time.Second, 3)
+
+	for i := 0; i < 3; i++ {
+		if !rl.Allow() {
+			t.Fatalf("Allow() #%d == false; Wanted true", i+1)
+		}
+	}
+
+	if rl.Allow() {
+		t.Errorf("Allow() with empty bucket == true; Wanted false")
+	}
+
+	clock = clock.Add(time.Second)
+	if !rl.Allow() || rl.Allow() {
+		t.Errorf("Expected exactly one token after one interval")
+	}
+
+	// The bucket holds no more than burst tokens, no matter how long it's idle
+	clock = clock.Add(time.Hour)
+
+	allowed := 0
+	for rl.Allow() {
+		allowed++
+	}
+
+	if allowed != 3 {
+		t.Errorf("Allowed %d events after idling; Wanted 3", allowed)
+	}
+
+	unlimited := NewRateLimiter(0, 0)
+	for i := 0; i < 100; i++ {
+		if !unlimited.Allow() {
+			t.Fatalf("Allow() from unlimited RateLimiter == false")
+		}
+	}
+}
+
+func TestRateLimiterReserve(t *testing.T) {
+	defer resetTimeFuncs()
+
+	clock := now
+	timeNow = func() time.Time { return clock }
+
+	rl := NewRateLimiter(time.Second, 2)
+
+	var delays []time.Duration
+	for i := 0; i < 4; i++ {
+		delays = append(delays, rl.Reserve().Delay())
+	}
+
+	if want := []time.Duration{0, 0, time.Second, 2 * time.Second}; !reflect.DeepEqual(delays, want) {
+		t.Errorf("Reserve() delays == %v; Wanted %v", delays, want)
+	}
+
+	r := rl.Reserve()
+	if r.Delay() != 3*time.Second || !r.Time().Equal(now.Add(3*time.Second)) {
+		t.Errorf("Reserve() == %v (delay %v); Wanted %v", r.Time(), r.Delay(), now.Add(3*time.Second))
+	}
+
+	r.Cancel()
+	r.Cancel()
+
+	if d := rl.Reserve().Delay(); d != 3*time.Second {
+		t.Errorf("Reserve() after Cancel() has delay %v; Wanted 3s", d)
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	defer resetTimeFuncs()
+
+	fired := make(chan time.Time)
+	close(fired)
+
+	clock := now
+	timeNow = func() time.Time { return clock }
+	timeNewTimer = func(d time.Duration) timer {
+		clock = clock.Add(d)
+		return &fakeTimer{c: fired}
+	}
+
+	rl := NewRateLimiter(time.Minute, 1)
+
+	for i := 0; i < 3; i++ {
+		if err := rl.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() == %v; Wanted <nil>", err)
+		}
+	}
+
+	if want := now.Add(2 * time.Minute); !clock.Equal(want) {
+		t.Errorf("After 3 Waits, clock == %v; Wanted %v", clock, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	timeNewTimer = func(time.Duration) timer {
+		return &fakeTimer{} // never fires
+	}
+
+	if err := rl.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() with canceled context == %v; Wanted %v", err, context.Canceled)
+	}
+
+	// The canceled Wait must have returned its token.
+	if d := rl.Reserve().Delay(); d != time.Minute {
+		t.Errorf("Reserve() after canceled Wait has delay %v; Wanted 1m", d)
+	}
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	defer resetTimeFuncs()
+
+	timeNow = func() time.Time { return now }
+
+	rl := NewRateLimiter(time.Second, 10)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if rl.Allow() {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if allowed != 10 {
+		t.Errorf("Allowed %d concurrent events; Wanted 10", allowed)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// RunOption is an optional setting that may be passed to RunEvery.
+type RunOption func(*runConfig)
+
+type runConfig struct {
+	limit   int
+	queue   bool
+	recover bool
+	timeout time.Duration
+	onError func(error)
+}
+
+// WithMaxConcurrency returns a RunOption limiting the number of concurrent
+// runs to n. The default is 1 (i.e. runs never overlap). A value of n less
+// than 1 is treated as 1.
+//
+// By default, a tick that arrives while n runs are already in flight is
+// skipped; see WithQueueWhenBusy.
+func WithMaxConcurrency(n int) RunOption {
+	return func(rc *runConfig) {
+		if n < 1 {
+			n = 1
+		}
+		rc.limit = n
+	}
+}
+
+// WithQueueWhenBusy returns a RunOption causing a tick that arrives while the
+// maximum number of runs are already in flight to wait for one of them to
+// finish instead of being skipped. While a tick waits, no further ticks are
+// received; how they're handled is then up to the ticker (see
+// OverrunPolicy).
+func WithQueueWhenBusy() RunOption {
+	return func(rc *runConfig) {
+		rc.queue = true
+	}
+}
+
+// WithPanicRecovery returns a RunOption that recovers a panicking run and
+// converts it to a *PanicError, which is passed to the error handler (if
+// any). Without this option, a panicking run crashes the program.
+func WithPanicRecovery() RunOption {
+	return func(rc *runConfig) {
+		rc.recover = true
+	}
+}
+
+// WithRunTimeout returns a RunOption that limits each run to d. The Context
+// passed to each run is cancelled once d has elapsed, with ErrRunTimeout as
+// its cause (see context.Cause).
+func WithRunTimeout(d time.Duration) RunOption {
+	return func(rc *runConfig) {
+		rc.timeout = d
+	}
+}
+
+// WithErrorHandler returns a RunOption that calls fn with each non-nil error
+// returned by a run. Note that fn may be called concurrently if
+// WithMaxConcurrency allows more than one run at a time.
+func WithErrorHandler(fn func(error)) RunOption {
+	return func(rc *runConfig) {
+		rc.onError = fn
+	}
+}
+
+// RunEvery calls fn, in its own goroutine, each time a value is received from
+// ticks (e.g. the C channel of a NormalTicker) until ctx is cancelled or ticks
+// is closed. The Context passed to fn is derived from ctx. Errors returned by
+// fn are discarded unless an error handler is provided using
+// WithErrorHandler.
+//
+// RunEvery does not return until all in-flight runs have finished. It
+// returns ctx.Err() (wrapping its cause, if any) if ctx is cancelled or nil
+// if ticks is closed.
+func RunEvery(ctx context.Context, ticks <-chan time.Time, fn func(context.Context) error, opts ...RunOption) error {
+	rc := &runConfig{limit: 1}
+	for _, o := range opts {
+		o(rc)
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	sem := make(chan struct{}, rc.limit)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return contextErr(ctx)
+
+		case _, ok := <-ticks:
+			if !ok {
+				return nil
+			}
+		}
+
+		if !rc.acquire(ctx, sem) {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			rc.run(ctx, fn)
+		}()
+	}
+}
+
+func (rc *runConfig) acquire(ctx context.Context, sem chan struct{}) bool {
+	if !rc.queue {
+		select {
+		case sem <- struct{}{}:
+			return true
+		default:
+			return false
+		}
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (rc *runConfig) run(ctx context.Context, fn func(context.Context) error) {
+	if rc.timeout > 0 {
+		var cancel context.CancelCauseFunc
+		ctx, cancel = context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		cancelAfter(ctx, cancel, rc.timeout, ErrRunTimeout)
+	}
+
+	if err := rc.call(ctx, fn); err != nil && rc.onError != nil {
+		rc.onError(err)
+	}
+}
+
+func (rc *runConfig) call(ctx context.Context, fn func(context.Context) error) (err error) {
+	if rc.recover {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+	}
+
+	return fn(ctx)
+}
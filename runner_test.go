@@ -0,0 +1,138 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunEveryBusy(t *testing.T) {
+	cases := []struct {
+		name string
+		opts []RunOption
+		want int32
+	}{
+		{"Skip", nil, 1},
+		{"Queue", []RunOption{WithQueueWhenBusy()}, 2},
+		{"Concurrent", []RunOption{WithMaxConcurrency(2)}, 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ticks := make(chan time.Time)
+			started := make(chan struct{}, 2)
+			release := make(chan struct{})
+
+			var runs, finished int32
+
+			fn := func(context.Context) error {
+				atomic.AddInt32(&runs, 1)
+				started <- struct{}{}
+				<-release
+				atomic.AddInt32(&finished, 1)
+				return nil
+			}
+
+			errc := make(chan error)
+			go func() { errc <- RunEvery(ctx, ticks, fn, tc.opts...) }()
+
+			ticks <- now
+			<-started
+			ticks <- now
+
+			switch tc.name {
+			case "Skip":
+				ticks <- now // ensures the second tick has been handled
+				cancel()
+				close(release)
+
+			case "Queue":
+				close(release)
+				<-started
+				cancel()
+
+			case "Concurrent":
+				<-started
+				cancel()
+				close(release)
+			}
+
+			if err := <-errc; err != context.Canceled {
+				t.Errorf("RunEvery() == %v; Wanted %v", err, context.Canceled)
+			}
+
+			if got := atomic.LoadInt32(&runs); got != tc.want {
+				t.Errorf("got %d runs; wanted %d", got, tc.want)
+			}
+
+			if got := atomic.LoadInt32(&finished); got != tc.want {
+				t.Errorf("RunEvery returned with %d of %d runs finished", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunEveryErrors(t *testing.T) {
+	boom := errors.New("boom")
+
+	cases := []struct {
+		name string
+		fn   func(context.Context) error
+		opts []RunOption
+		want error
+	}{
+		{"Error", func(context.Context) error { return boom }, nil, boom},
+		{"Panic", func(context.Context) error { panic(boom) }, []RunOption{WithPanicRecovery()}, boom},
+		{"Timeout", func(ctx context.Context) error { <-ctx.Done(); return context.Cause(ctx) }, []RunOption{WithRunTimeout(time.Hour)}, ErrRunTimeout},
+	}
+
+	// The run timeout's timer fires at once.
+	defer resetTimeFuncs()
+
+	fired := make(chan time.Time)
+	close(fired)
+
+	timeNewTimer = func(time.Duration) timer {
+		return &fakeTimer{c: fired}
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mu   sync.Mutex
+				errs []error
+			)
+
+			handler := WithErrorHandler(func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, err)
+			})
+
+			ticks := make(chan time.Time, 1)
+			ticks <- now
+			close(ticks)
+
+			if err := RunEvery(context.Background(), ticks, tc.fn, append(tc.opts, handler)...); err != nil {
+				t.Errorf("RunEvery() == %v; Wanted nil", err)
+			}
+
+			if len(errs) != 1 || !errors.Is(errs[0], tc.want) {
+				t.Fatalf("error handler called with %v; Wanted [%v]", errs, tc.want)
+			}
+
+			var pe *PanicError
+			if isPanic := errors.As(errs[0], &pe); isPanic != (tc.name == "Panic") {
+				t.Errorf("error handler called with %T", errs[0])
+			}
+		})
+	}
+}
@@ -22,32 +22,174 @@ package timetool
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"time"
 )
 
 // Sleep is a wrapper around time.Sleep that may be interrupted by the
 // cancellation of a Context. Sleep returns ctx.Err() if cancelled by
-// the Context, otherwise it returns nil.
+// the Context, otherwise it returns nil. If the Context was cancelled with a
+// cause (see context.WithCancelCause), the returned error wraps both
+// ctx.Err() and the cause.
 func Sleep(ctx context.Context, d time.Duration) error {
-	if d == 0 {
-		return nil
+	_, err := SleepRemaining(ctx, d)
+	return err
+}
+
+// SleepRemaining is like Sleep but also returns the portion of d that had
+// not yet elapsed when it returned. The remaining time is zero unless the
+// sleep was interrupted by ctx, in which case ctx.Err() is also returned.
+//
+// If d is not positive, SleepRemaining returns immediately with a zero
+// remaining time and a nil error.
+func SleepRemaining(ctx context.Context, d time.Duration) (time.Duration, error) {
+	if d <= 0 {
+		return 0, nil
 	}
 
-	var err error
-	ch := timeAfter(d)
+	start := timeNow()
+	t := timeNewTimer(d)
+
+	// Stopping the timer releases it immediately, rather than when it would
+	// have fired, if the sleep is interrupted.
+	defer t.Stop()
+
 	select {
 	case <-ctx.Done():
-		err = ctx.Err()
+		rem := d - timeNow().Sub(start)
+		if rem < 0 {
+			rem = 0
+		}
+		return rem, contextErr(ctx)
 
-	case <-ch:
-		err = nil
+	case <-t.Chan():
+		return 0, nil
 	}
-
-	return err
 }
 
 // SleepUntil is a wrapper around Sleep that accepts a time.Time instead
-// of a time.Duration.
+// of a time.Duration. If t is not in the future, SleepUntil returns nil
+// immediately. When interrupted by ctx, the time remaining is simply the
+// time until t.
 func SleepUntil(ctx context.Context, t time.Time) error {
-	return Sleep(ctx, t.Sub(timeNow()))
+	d := t.Sub(timeNow())
+	if d <= 0 {
+		return nil
+	}
+
+	return Sleep(ctx, d)
+}
+
+// wallRecheck is the maximum amount of time a wall clock bound wait will
+// go without consulting the clock, by default.
+const wallRecheck = time.Minute
+
+// SleepUntilWall is like SleepUntil except it tracks the wall clock. Since
+// SleepUntil converts t to a duration once, based on the monotonic clock, it
+// wakes at the wrong wall clock time if the host is suspended or its wall
+// clock is stepped (e.g. by NTP) in the meantime. SleepUntilWall instead
+// sleeps in increments of no more than recheck, comparing the wall clock to t
+// after each, and returns once t has been reached. If recheck is not
+// positive, a default of one minute is used.
+//
+// Finer recheck granularity allows for a more timely response to a jump in
+// the wall clock at the cost of additional wakeups.
+func SleepUntilWall(ctx context.Context, t time.Time, recheck time.Duration) error {
+	if recheck <= 0 {
+		recheck = wallRecheck
+	}
+
+	// Stripping the monotonic clock reading ensures that all comparisons
+	// against t use the wall clock.
+	t = t.Round(0)
+
+	for {
+		d := t.Sub(timeNow())
+		if d <= 0 {
+			return nil
+		}
+
+		if d > recheck {
+			d = recheck
+		}
+
+		if err := Sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+// WakeReason indicates why SleepSelect returned. Non-negative values are the
+// index of the waker channel that ended the sleep.
+type WakeReason int
+
+const (
+	// WakeTimeout indicates that the full sleep duration elapsed.
+	WakeTimeout WakeReason = -1
+
+	// WakeDone indicates that the sleep was interrupted by its Context.
+	WakeDone WakeReason = -2
+)
+
+func (wr WakeReason) String() string {
+	switch wr {
+	case WakeTimeout:
+		return "timeout"
+	case WakeDone:
+		return "context done"
+	default:
+		return fmt.Sprintf("waker #%d", int(wr))
+	}
+}
+
+// SleepSelect is like Sleep but may also be woken by a receive from (or the
+// closing of) any of the given wakers. The returned WakeReason indicates
+// whether the sleep ran its full duration (WakeTimeout), was interrupted by
+// ctx (WakeDone), or was woken by a waker, in which case it is that waker's
+// index. A nil waker never wakes the sleep.
+//
+// The returned error is as described for Sleep if the sleep was interrupted
+// by ctx, otherwise it is nil. If d is not positive, SleepSelect returns
+// WakeTimeout immediately.
+//
+// SleepSelect waits on the same timer as Sleep, but it is not built on top of
+// Sleep. Doing so would require a goroutine per waker to forward its wakeup,
+// and each such goroutine could receive a value from its waker after the
+// sleep had already ended -- a value intended for some other receiver.
+// Instead, all channels are waited on by a single select (using
+// reflect.Select, since the number of wakers varies), which receives from at
+// most one of them.
+func SleepSelect(ctx context.Context, d time.Duration, wakers ...<-chan struct{}) (WakeReason, error) {
+	if len(wakers) == 0 {
+		if err := Sleep(ctx, d); err != nil {
+			return WakeDone, err
+		}
+		return WakeTimeout, nil
+	}
+
+	if d <= 0 {
+		return WakeTimeout, nil
+	}
+
+	t := timeNewTimer(d)
+	defer t.Stop()
+
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(t.Chan())},
+	}
+
+	for _, w := range wakers {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(w)})
+	}
+
+	switch i, _, _ := reflect.Select(cases); i {
+	case 0:
+		return WakeDone, contextErr(ctx)
+	case 1:
+		return WakeTimeout, nil
+	default:
+		return WakeReason(i - 2), nil
+	}
 }
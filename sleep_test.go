@@ -22,6 +22,7 @@ package timetool
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -41,11 +42,11 @@ func TestSleep(t *testing.T) {
 		}()
 	}
 
-	timeAfter = func(d time.Duration) <-chan time.Time {
+	timeNewTimer = func(d time.Duration) timer {
 		if d != sd {
-			t.Errorf("bad arg1 to time.After(); Got %v; Wanted %v", d, sd)
+			t.Errorf("bad arg1 to time.NewTimer(); Got %v; Wanted %v", d, sd)
 		}
-		return ch
+		return &fakeTimer{c: ch}
 	}
 
 	t.Run("Cancelled", func(t *testing.T) {
@@ -74,3 +75,188 @@ func doTestSleep(ctx context.Context, prepFunc func(), d time.Duration, want err
 
 	return nil
 }
+
+func TestSleepRemaining(t *testing.T) {
+	defer resetTimeFuncs()
+
+	clock := now
+	timeNow = func() time.Time {
+		defer func() { clock = clock.Add(400 * time.Millisecond) }()
+		return clock
+	}
+
+	timeNewTimer = func(time.Duration) timer {
+		return &fakeTimer{} // never fires
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if rem, err := SleepRemaining(ctx, time.Second); rem != 600*time.Millisecond || err != context.Canceled {
+		t.Errorf("SleepRemaining(ctx, 1s) == (%v, %v); Wanted (600ms, %v)", rem, err, context.Canceled)
+	}
+
+	for _, d := range []time.Duration{0, -time.Second} {
+		if rem, err := SleepRemaining(ctx, d); rem != 0 || err != nil {
+			t.Errorf("SleepRemaining(ctx, %v) == (%v, %v); Wanted (0s, <nil>)", d, rem, err)
+		}
+	}
+
+	if err := SleepUntil(ctx, now.Add(-time.Hour)); err != nil {
+		t.Errorf("SleepUntil(ctx, <past>) == %v; Wanted <nil>", err)
+	}
+}
+
+func TestSleepStopsTimer(t *testing.T) {
+	defer resetTimeFuncs()
+
+	ft := &fakeTimer{}
+	timeNewTimer = func(time.Duration) timer {
+		return ft
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := Sleep(ctx, time.Hour); err != context.Canceled {
+		t.Errorf("Sleep(ctx, 1h) == %v; Wanted %v", err, context.Canceled)
+	}
+
+	if !ft.stopped {
+		t.Error("timer not stopped after Sleep was cancelled")
+	}
+}
+
+func BenchmarkSleepCancelled(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		Sleep(ctx, time.Hour)
+	}
+}
+
+// BenchmarkTimeAfterCancelled measures the previous, time.After based,
+// implementation of Sleep for comparison with BenchmarkSleepCancelled.
+func BenchmarkTimeAfterCancelled(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Hour):
+		}
+	}
+}
+
+// fakeTimer is a timer whose channel is controlled by the test.
+type fakeTimer struct {
+	c       chan time.Time
+	stopped bool
+}
+
+func (ft *fakeTimer) Chan() <-chan time.Time {
+	return ft.c
+}
+
+func (ft *fakeTimer) Stop() bool {
+	ft.stopped = true
+	return true
+}
+
+func (ft *fakeTimer) Reset(time.Duration) bool {
+	ft.stopped = false
+	return true
+}
+
+func TestSleepSelect(t *testing.T) {
+	defer resetTimeFuncs()
+
+	fired := make(chan time.Time, 1)
+	fired <- now
+
+	woke := make(chan struct{})
+	close(woke)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := []struct {
+		name    string
+		ctx     context.Context
+		timer   chan time.Time
+		wakers  []<-chan struct{}
+		want    WakeReason
+		wantErr error
+	}{
+		{"Timeout", context.Background(), fired, []<-chan struct{}{nil, make(chan struct{})}, WakeTimeout, nil},
+		{"TimeoutNoWakers", context.Background(), fired, nil, WakeTimeout, nil},
+		{"Done", cancelled, nil, []<-chan struct{}{make(chan struct{})}, WakeDone, context.Canceled},
+		{"DoneNoWakers", cancelled, nil, nil, WakeDone, context.Canceled},
+		{"Waker", context.Background(), nil, []<-chan struct{}{make(chan struct{}), woke}, 1, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			timeNewTimer = func(time.Duration) timer {
+				return &fakeTimer{c: tc.timer}
+			}
+
+			if got, err := SleepSelect(tc.ctx, time.Second, tc.wakers...); got != tc.want || err != tc.wantErr {
+				t.Errorf("SleepSelect() == (%v, %v); Wanted (%v, %v)", got, err, tc.want, tc.wantErr)
+			}
+
+			if len(fired) == 0 {
+				fired <- now
+			}
+		})
+	}
+}
+
+func TestSleepUntilWall(t *testing.T) {
+	defer resetTimeFuncs()
+
+	fired := make(chan time.Time)
+	close(fired)
+
+	clock := now
+	timeNow = func() time.Time {
+		return clock
+	}
+
+	// The wall clock jumps forward (e.g. after a suspend) during the second
+	// wait.
+	var waits []time.Duration
+	timeNewTimer = func(d time.Duration) timer {
+		if waits = append(waits, d); len(waits) == 2 {
+			d += 90 * time.Second
+		}
+		clock = clock.Add(d)
+		return &fakeTimer{c: fired}
+	}
+
+	if err := SleepUntilWall(context.Background(), now.Add(3*time.Minute), time.Minute); err != nil {
+		t.Fatalf("SleepUntilWall() == %v; Wanted <nil>", err)
+	}
+
+	if want := []time.Duration{time.Minute, time.Minute}; fmt.Sprint(waits) != fmt.Sprint(want) {
+		t.Errorf("SleepUntilWall() waited %v; Wanted %v", waits, want)
+	}
+}
+
+func TestSleepCause(t *testing.T) {
+	shutdown := errors.New("shutting down")
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(shutdown)
+
+	err := Sleep(ctx, time.Hour)
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, shutdown) {
+		t.Errorf("Sleep() == %v; Wanted a match for %v and %v", err, context.Canceled, shutdown)
+	}
+}
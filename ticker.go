@@ -8,100 +8,327 @@ import (
 
 // Type NormalTicker holds a channel that delivers "ticks" of a clock over
 // a normally distributed time interval.
+//
+// Each tick is delivered on exactly one of C or T; a receiver interested in
+// a tick's metadata should receive from T instead of C.
 type NormalTicker struct {
-	C      chan time.Time
-	done   chan struct{}
+	C chan time.Time
+	T chan Tick
+	ticker
 	mean   time.Duration
 	stddev time.Duration
-	err    error
 }
 
 // NewNormalTicker returns a new NormalTicker containing a channel that will
 // send the current time on the channel after each tick. The period of the
 // ticks is over a normal distribution as specified by the mean and stddev
-// arguments. The ticker will drop ticks to make up for slow receivers and
-// will continue to send values to its channel until the Stop method is called
-// or the given context is expired.
-func NewNormalTicker(ctx context.Context, mean, stddev time.Duration) *NormalTicker {
+// arguments. By default, a slow receiver pauses the ticker, which then
+// restarts its interval once the waiting tick is received (see OverrunBlock
+// and WithOverrunPolicy). The ticker will continue to send values to its
+// channel until the Stop method is called or the given context is expired.
+func NewNormalTicker(ctx context.Context, mean, stddev time.Duration, opts ...TickerOption) *NormalTicker {
 	nt := &NormalTicker{
 		C:      make(chan time.Time),
-		done:   make(chan struct{}),
+		T:      make(chan Tick),
 		mean:   mean,
 		stddev: stddev,
 	}
 
+	nt.ticker = newTicker(nt.C, nt.T, nt.next, 0, append([]TickerOption{WithOverrunPolicy(OverrunBlock)}, opts...))
+
 	go nt.run(ctx)
 
 	return nt
 }
 
+func (nt *NormalTicker) next(t time.Time) time.Time {
+	return t.Add(nt.duration())
+}
+
+func (nt *NormalTicker) duration() time.Duration {
+	return time.Duration(rand.NormFloat64()*float64(nt.stddev) + float64(nt.mean))
+}
+
+// Tick describes a single tick delivered by one of this package's tickers.
+type Tick struct {
+	// Seq is the sequence number of this tick. Every scheduled tick is
+	// numbered, starting from 1, including those that are dropped.
+	Seq uint64
+
+	// Scheduled is the time at which this tick was scheduled to occur.
+	Scheduled time.Time
+
+	// Delivered is the time at which the ticker began offering this tick to
+	// its receivers: when it fired or, if ticks were waiting ahead of it
+	// (see OverrunQueue), when the last of those was received. This is the
+	// value that would be sent on the ticker's C channel. Since a channel
+	// send cannot report when its value is received, any further wait for
+	// the receiver to arrive is not included.
+	Delivered time.Time
+
+	// Dropped is the number of ticks dropped between the previously
+	// delivered tick and this one.
+	Dropped int
+}
+
+// Late returns how far behind its schedule the tick was delivered. For a
+// ticker that queues ticks, this grows as a slow receiver falls behind.
+func (t Tick) Late() time.Duration {
+	return t.Delivered.Sub(t.Scheduled)
+}
+
+// OverrunPolicy determines how a ticker behaves when a tick comes due while
+// a previous tick is still waiting to be received.
+type OverrunPolicy struct {
+	mode  overrunMode
+	limit int
+}
+
+type overrunMode int
+
+const (
+	overrunDrop overrunMode = iota
+	overrunLatest
+	overrunQueue
+	overrunBlock
+)
+
+var (
+	// OverrunDrop keeps the waiting tick and drops the new one. This is the
+	// default policy for AlignedTicker and CronTicker and mirrors the
+	// behavior of a time.Ticker.
+	OverrunDrop = OverrunPolicy{mode: overrunDrop, limit: 1}
+
+	// OverrunLatest drops the waiting tick in favor of the new one, so that
+	// a slow receiver always receives the most recent tick.
+	OverrunLatest = OverrunPolicy{mode: overrunLatest, limit: 1}
+
+	// OverrunBlock pauses the ticker until the waiting tick is received and
+	// then restarts its interval from that moment. Any ticks that would have
+	// come due in the meantime are skipped entirely and are not considered
+	// dropped. This is the default policy for NormalTicker.
+	OverrunBlock = OverrunPolicy{mode: overrunBlock, limit: 1}
+)
+
+// OverrunQueue returns an OverrunPolicy that holds up to n ticks waiting to
+// be received, in the order they came due. Once n ticks are waiting, new
+// ticks are dropped. A value of n less than 1 is treated as 1 (which is then
+// equivalent to OverrunDrop).
+func OverrunQueue(n int) OverrunPolicy {
+	if n < 1 {
+		n = 1
+	}
+	return OverrunPolicy{mode: overrunQueue, limit: n}
+}
+
+// TickerOption is an optional setting that may be passed to any of this
+// package's ticker constructors.
+type TickerOption func(*ticker)
+
+// WithOverrunPolicy returns a TickerOption that sets the ticker's
+// OverrunPolicy. The default is OverrunBlock for a NormalTicker and
+// OverrunDrop for all other tickers.
+func WithOverrunPolicy(p OverrunPolicy) TickerOption {
+	return func(tk *ticker) {
+		if p.limit < 1 {
+			p.limit = 1
+		}
+		tk.policy = p
+	}
+}
+
+// ticker holds the machinery shared by each of this package's ticker types.
+// Its next func returns the time of the first tick scheduled after the given
+// time, or the zero Time if no more ticks are scheduled. If recheck is
+// non-zero, the clock is consulted at least this often while waiting for a
+// tick; this allows a ticker whose schedule is bound to the wall clock to
+// notice when the wall clock jumps (e.g. after the host has been suspended or
+// its time has been stepped).
+//
+// Unless the ticker's policy is OverrunBlock, its timer continues to run
+// while ticks are waiting for a receiver.
+type ticker struct {
+	c       chan time.Time
+	t       chan Tick
+	done    chan struct{}
+	exited  chan struct{}
+	next    func(time.Time) time.Time
+	recheck time.Duration
+	policy  OverrunPolicy
+	err     error
+}
+
+func newTicker(c chan time.Time, t chan Tick, next func(time.Time) time.Time, recheck time.Duration, opts []TickerOption) ticker {
+	tk := ticker{
+		c:       c,
+		t:       t,
+		done:    make(chan struct{}),
+		exited:  make(chan struct{}),
+		next:    next,
+		recheck: recheck,
+		policy:  OverrunDrop,
+	}
+
+	for _, o := range opts {
+		o(&tk)
+	}
+
+	return tk
+}
+
 // Stop turns off the ticker. After Stop, no more ticks will be sent. Stop does
 // not close the channel, to prevent a concurrent goroutine reading from the
 // channel from seeing an erroneous "tick". If Stop is called before the
 // constructor's Context has expired, the Err method will return a nil error.
-func (nt *NormalTicker) Stop() {
-	close(nt.done)
+// Stop returns once the ticker's goroutine has exited.
+func (tk *ticker) Stop() {
+	close(tk.done)
+	<-tk.exited
 }
 
 // Err returns an error indicating how the ticker was stopped. If the Stop
-// method was called, a nil error returned. If the constructor's Context has
-// expired, ctx.Err() is returned. If the ticker has not been stopped,
-// ErrTickerActive is returned.
-func (nt *NormalTicker) Err() error {
-	return nt.err
+// method was called, a nil error is returned. If the constructor's Context
+// has expired, ctx.Err() is returned (wrapping the Context's cause, if it has
+// one). If the ticker's schedule has no further ticks, ErrScheduleExhausted
+// is returned. If the ticker has not yet stopped, ErrTickerActive is
+// returned.
+func (tk *ticker) Err() error {
+	select {
+	case <-tk.exited:
+		return tk.err
+	default:
+		return ErrTickerActive
+	}
 }
 
-func (nt *NormalTicker) run(ctx context.Context) {
-	t := time.NewTimer(nt.duration())
+func (tk *ticker) run(ctx context.Context) {
+	defer close(tk.exited)
+
+	var (
+		seq     uint64
+		dropped int
+		pending []Tick
+		paused  bool
+	)
+
+	target := tk.next(timeNow())
+	if target.IsZero() {
+		tk.err = ErrScheduleExhausted
+		return
+	}
+
+	t := timeNewTimer(tk.wait(target))
 
 	defer stopAndFlush(t)
 
 	for {
-		if done, err := nt.onePass(ctx, t); done || err != nil {
-			nt.err = err
-			return
+		// Sends are only enabled (i.e. the channels are non-nil) while a tick
+		// is pending.
+		var (
+			c    chan time.Time
+			tc   chan Tick
+			tick Tick
+		)
+
+		if len(pending) > 0 {
+			c, tc, tick = tk.c, tk.t, pending[0]
 		}
 
-		t.Reset(nt.duration())
-	}
-}
+		select {
+		case <-ctx.Done():
+			tk.err = contextErr(ctx)
+			return
 
-func (nt *NormalTicker) onePass(ctx context.Context, tt *time.Timer) (bool, error) {
-	var tv time.Time
+		case <-tk.done:
+			return
 
-	select {
-	case <-ctx.Done():
-		return true, ctx.Err()
+		case tv := <-t.Chan():
+			if d := tk.wait(target); d > 0 {
+				t.Reset(d)
+				continue
+			}
 
-	case <-nt.done:
-		return true, nil
+			seq++
+			nt := Tick{Seq: seq, Scheduled: target, Delivered: tv, Dropped: dropped}
 
-	case tv = <-tt.C:
-	}
+			switch {
+			case len(pending) < tk.policy.limit:
+				// A tick queued behind others is stamped once it reaches
+				// the head of the queue.
+				if len(pending) > 0 {
+					nt.Delivered = time.Time{}
+				}
+				pending = append(pending, nt)
+				dropped = 0
 
-	select {
-	case <-ctx.Done():
-		return true, ctx.Err()
+			case tk.policy.mode == overrunLatest:
+				nt.Dropped += pending[0].Dropped + 1
+				pending[0] = nt
+				dropped = 0
+
+			default:
+				dropped++
+			}
+
+			if tk.policy.mode == overrunBlock {
+				paused = true
+				continue
+			}
+
+			if target = tk.next(timeNow()); target.IsZero() {
+				tk.err = ErrScheduleExhausted
+				return
+			}
+
+			t.Reset(tk.wait(target))
+
+		case c <- tick.Delivered:
+			pending = shiftTicks(pending)
+
+		case tc <- tick:
+			pending = shiftTicks(pending)
+		}
 
-	case <-nt.done:
-		return true, nil
+		if paused && len(pending) == 0 {
+			paused = false
 
-	case nt.C <- tv:
-		return false, nil
+			if target = tk.next(timeNow()); target.IsZero() {
+				tk.err = ErrScheduleExhausted
+				return
+			}
+
+			t.Reset(tk.wait(target))
+		}
 	}
 }
 
-func (nt *NormalTicker) duration() time.Duration {
-	return time.Duration(rand.NormFloat64()*float64(nt.stddev) + float64(nt.mean))
+// shiftTicks removes the first of the pending ticks, which has just been
+// received, and stamps the next (if any) as now being delivered.
+func shiftTicks(pending []Tick) []Tick {
+	pending = pending[1:]
+	if len(pending) > 0 {
+		pending[0].Delivered = timeNow()
+	}
+	return pending
+}
+
+// wait returns how long to wait before target is reached -- or, at most, the
+// ticker's recheck interval.
+func (tk *ticker) wait(target time.Time) time.Duration {
+	d := target.Sub(timeNow())
+	if tk.recheck > 0 && d > tk.recheck {
+		d = tk.recheck
+	}
+	return d
 }
 
-func stopAndFlush(t *time.Timer) {
+func stopAndFlush(t timer) {
 	if t == nil || t.Stop() {
 		return
 	}
 
 	select {
-	case <-t.C:
+	case <-t.Chan():
 	default:
 	}
 }
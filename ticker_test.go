@@ -2,7 +2,9 @@ package timetool
 
 import (
 	"context"
+	"errors"
 	"math/rand"
+	"sync"
 	"testing"
 	"time"
 )
@@ -10,6 +12,7 @@ import (
 func TestNormalTicker(t *testing.T) {
 	rand.Seed(1)
 	nt := NewNormalTicker(context.Background(), 75*time.Millisecond, 15.*time.Millisecond)
+	defer nt.Stop()
 
 	var pt time.Time
 
@@ -31,3 +34,355 @@ func TestNormalTicker(t *testing.T) {
 func plusOrMinusOne(a, b int64) bool {
 	return a >= b-1 && a <= b+1
 }
+
+// fakeClock is a manually advanced clock, installed as the package clock by
+// newFakeClock, for testing code that waits on timers from its own goroutine.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*clockTimer
+	armed  chan struct{}
+}
+
+type clockTimer struct {
+	fc     *fakeClock
+	c      chan time.Time
+	at     time.Time
+	active bool
+}
+
+func newFakeClock(t *testing.T, start time.Time) *fakeClock {
+	fc := &fakeClock{now: start, armed: make(chan struct{}, 100)}
+
+	timeNow = fc.Now
+	timeNewTimer = fc.newTimer
+	t.Cleanup(resetTimeFuncs)
+
+	return fc
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+func (fc *fakeClock) newTimer(d time.Duration) timer {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	ct := &clockTimer{fc: fc, c: make(chan time.Time, 1)}
+	fc.timers = append(fc.timers, ct)
+	ct.arm(d)
+
+	return ct
+}
+
+// Advance moves the clock forward by d, firing any timers that come due.
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	fc.now = fc.now.Add(d)
+	fc.fireDue()
+}
+
+// Fire waits for a timer to be armed (or re-armed) and then moves the clock
+// forward to the earliest deadline among the active timers, firing it.
+func (fc *fakeClock) Fire(t *testing.T) {
+	t.Helper()
+
+	select {
+	case <-fc.armed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a timer to be armed")
+	}
+
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	var next time.Time
+	for _, ct := range fc.timers {
+		if ct.active && (next.IsZero() || ct.at.Before(next)) {
+			next = ct.at
+		}
+	}
+
+	if next.After(fc.now) {
+		fc.now = next
+	}
+
+	fc.fireDue()
+}
+
+// fireDue must be called with fc.mu held.
+func (fc *fakeClock) fireDue() {
+	for _, ct := range fc.timers {
+		if ct.active && !ct.at.After(fc.now) {
+			ct.active = false
+			select {
+			case ct.c <- fc.now:
+			default:
+			}
+		}
+	}
+}
+
+// arm must be called with fc.mu held.
+func (ct *clockTimer) arm(d time.Duration) {
+	ct.at = ct.fc.now.Add(d)
+	ct.active = true
+
+	select {
+	case ct.fc.armed <- struct{}{}:
+	default:
+	}
+}
+
+func (ct *clockTimer) Chan() <-chan time.Time {
+	return ct.c
+}
+
+func (ct *clockTimer) Stop() bool {
+	ct.fc.mu.Lock()
+	defer ct.fc.mu.Unlock()
+
+	active := ct.active
+	ct.active = false
+	return active
+}
+
+func (ct *clockTimer) Reset(d time.Duration) bool {
+	ct.fc.mu.Lock()
+	defer ct.fc.mu.Unlock()
+
+	active := ct.active
+	ct.arm(d)
+	return active
+}
+
+func TestTickerErr(t *testing.T) {
+	newFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	nt := NewNormalTicker(context.Background(), time.Second, 0)
+
+	if err := nt.Err(); err != ErrTickerActive {
+		t.Errorf("Err() before Stop == %v; Wanted %v", err, ErrTickerActive)
+	}
+
+	nt.Stop()
+
+	if err := nt.Err(); err != nil {
+		t.Errorf("Err() after Stop == %v; Wanted <nil>", err)
+	}
+
+	boom := errors.New("boom")
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	at := NewAlignedTicker(ctx, time.Second, 0, time.UTC)
+	cancel(boom)
+
+	// Without Stop, there is nothing to wait on but the ticker itself.
+	deadline := time.Now().Add(5 * time.Second)
+	for at.Err() == ErrTickerActive && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := at.Err(); !errors.Is(err, context.Canceled) || !errors.Is(err, boom) {
+		t.Errorf("Err() after cancel == %v; Wanted a match for %v and %v", err, context.Canceled, boom)
+	}
+}
+
+func TestTickMetadata(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 3*int(time.Millisecond), time.UTC)
+	fc := newFakeClock(t, start)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	period := 10 * time.Millisecond
+	at := NewAlignedTicker(ctx, period, 0, time.UTC)
+	defer at.Stop()
+
+	fc.Fire(t)
+	first := <-at.T
+
+	if first.Seq != 1 || first.Dropped != 0 {
+		t.Errorf("first tick: Seq=%d Dropped=%d; Wanted Seq=1 Dropped=0", first.Seq, first.Dropped)
+	}
+
+	if want := start.Truncate(period).Add(period); !first.Scheduled.Equal(want) || first.Late() != 0 {
+		t.Errorf("first tick scheduled for %v, %v late; Wanted %v, 0 late", first.Scheduled, first.Late(), want)
+	}
+
+	// Tick 2 is left pending while ticks 3 through 6 are dropped.
+	for i := 0; i < 5; i++ {
+		fc.Fire(t)
+	}
+
+	<-at.C
+	fc.Fire(t)
+	next := <-at.T
+
+	if next.Seq != 7 || next.Dropped != 4 {
+		t.Errorf("next tick: Seq=%d Dropped=%d; Wanted Seq=7 Dropped=4", next.Seq, next.Dropped)
+	}
+}
+
+func TestTickLateWhenQueued(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fc := newFakeClock(t, start)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	period := 10 * time.Millisecond
+	at := NewAlignedTicker(ctx, period, 0, time.UTC, WithOverrunPolicy(OverrunQueue(3)))
+	defer at.Stop()
+
+	for i := 0; i < 3; i++ {
+		fc.Fire(t)
+	}
+
+	// The receiver arrives 4ms after the third tick fired; each queued tick
+	// is stamped as delivered once the one ahead of it has been received.
+	fc.Advance(4 * time.Millisecond)
+
+	for i, want := range []time.Duration{0, 14 * time.Millisecond, 4 * time.Millisecond} {
+		if tk := <-at.T; tk.Late() != want {
+			t.Errorf("tick %d: Late() == %v; Wanted %v", i+1, tk.Late(), want)
+		}
+	}
+}
+
+func TestNormalTickerBlocks(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fc := newFakeClock(t, start)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	nt := NewNormalTicker(ctx, 10*time.Millisecond, 0)
+	defer nt.Stop()
+
+	// By default, a NormalTicker waits for its receiver and then restarts
+	// its interval.
+	fc.Fire(t)
+	fc.Advance(25 * time.Millisecond)
+
+	a := <-nt.T
+	received := fc.Now()
+
+	fc.Fire(t)
+	b := <-nt.T
+
+	if b.Seq != 2 || b.Dropped != 0 || !b.Scheduled.Equal(received.Add(10*time.Millisecond)) {
+		t.Errorf("second tick: Seq=%d Dropped=%d Scheduled=%v; Wanted Seq=2 Dropped=0 Scheduled=%v", b.Seq, b.Dropped, b.Scheduled, received.Add(10*time.Millisecond))
+	}
+
+	if !a.Scheduled.Equal(start.Add(10 * time.Millisecond)) {
+		t.Errorf("first tick scheduled for %v; Wanted %v", a.Scheduled, start.Add(10*time.Millisecond))
+	}
+}
+
+func TestOverrunPolicy(t *testing.T) {
+	const period = 10 * time.Millisecond
+
+	tickers := []struct {
+		name  string
+		start func(context.Context, ...TickerOption) (chan Tick, func())
+	}{
+		{"Normal", func(ctx context.Context, opts ...TickerOption) (chan Tick, func()) {
+			nt := NewNormalTicker(ctx, period, 0, opts...)
+			return nt.T, nt.Stop
+		}},
+		{"Aligned", func(ctx context.Context, opts ...TickerOption) (chan Tick, func()) {
+			at := NewAlignedTicker(ctx, period, 0, time.UTC, opts...)
+			return at.T, at.Stop
+		}},
+		{"Cron", func(ctx context.Context, opts ...TickerOption) (chan Tick, func()) {
+			ct := NewCronTicker(ctx, MustParseSchedule("@every 10ms"), opts...)
+			return ct.T, ct.Stop
+		}},
+	}
+
+	// Each check begins after 5 ticks have come due with none received.
+	policies := []struct {
+		name   string
+		policy OverrunPolicy
+		check  func(*testing.T, *fakeClock, chan Tick)
+	}{
+		{"Drop", OverrunDrop, func(t *testing.T, fc *fakeClock, tc chan Tick) {
+			if a := <-tc; a.Seq != 1 {
+				t.Errorf("first tick: Seq=%d; Wanted 1", a.Seq)
+			}
+
+			fc.Fire(t)
+
+			if b := <-tc; b.Seq != 6 || b.Dropped != 4 {
+				t.Errorf("second tick: Seq=%d Dropped=%d; Wanted Seq=6 Dropped=4", b.Seq, b.Dropped)
+			}
+		}},
+
+		{"Latest", OverrunLatest, func(t *testing.T, fc *fakeClock, tc chan Tick) {
+			if a := <-tc; a.Seq != 5 || a.Dropped != 4 {
+				t.Errorf("first tick: Seq=%d Dropped=%d; Wanted Seq=5 Dropped=4", a.Seq, a.Dropped)
+			}
+		}},
+
+		{"Queue", OverrunQueue(3), func(t *testing.T, fc *fakeClock, tc chan Tick) {
+			for i := uint64(1); i <= 3; i++ {
+				if a := <-tc; a.Seq != i || a.Dropped != 0 {
+					t.Errorf("tick %d: Seq=%d Dropped=%d; Wanted Seq=%d Dropped=0", i, a.Seq, a.Dropped, i)
+				}
+			}
+
+			fc.Fire(t)
+
+			if b := <-tc; b.Seq != 6 || b.Dropped != 2 {
+				t.Errorf("fourth tick: Seq=%d Dropped=%d; Wanted Seq=6 Dropped=2", b.Seq, b.Dropped)
+			}
+		}},
+
+		{"Block", OverrunBlock, func(t *testing.T, fc *fakeClock, tc chan Tick) {
+			a := <-tc
+			fc.Fire(t)
+			b := <-tc
+
+			if a.Seq != 1 || b.Seq != 2 || b.Dropped != 0 {
+				t.Errorf("ticks: Seq=%d,%d Dropped=%d; Wanted Seq=1,2 Dropped=0", a.Seq, b.Seq, b.Dropped)
+			}
+
+			if b.Scheduled.Before(a.Scheduled.Add(5 * period)) {
+				t.Errorf("second tick scheduled %v after first; Wanted at least %v", b.Scheduled.Sub(a.Scheduled), 5*period)
+			}
+		}},
+	}
+
+	for _, tt := range tickers {
+		for _, p := range policies {
+			t.Run(tt.name+"/"+p.name, func(t *testing.T) {
+				fc := newFakeClock(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				tc, stop := tt.start(ctx, WithOverrunPolicy(p.policy))
+				defer stop()
+
+				if p.policy == OverrunBlock {
+					// The paused ticker arms no timer until its tick
+					// is received.
+					fc.Fire(t)
+					fc.Advance(4 * period)
+				} else {
+					for i := 0; i < 5; i++ {
+						fc.Fire(t)
+					}
+				}
+
+				p.check(t, fc, tc)
+			})
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Range is the half-open span of time from Start up to, but not including,
+// End. A Range whose End is not after its Start is empty.
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// IsEmpty reports whether r contains no time at all.
+func (r Range) IsEmpty() bool {
+	return !r.End.After(r.Start)
+}
+
+// Duration returns the length of r, or zero if r is empty.
+func (r Range) Duration() time.Duration {
+	if r.IsEmpty() {
+		return 0
+	}
+	return r.End.Sub(r.Start)
+}
+
+// Contains reports whether t falls within r.
+func (r Range) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+// Overlaps reports whether r and o have any time in common. Adjacent ranges
+// (where one ends as the other starts) do not overlap.
+func (r Range) Overlaps(o Range) bool {
+	return !r.IsEmpty() && !o.IsEmpty() && r.Start.Before(o.End) && o.Start.Before(r.End)
+}
+
+// Intersect returns the time common to both r and o, or the zero Range if
+// they do not overlap.
+func (r Range) Intersect(o Range) Range {
+	if !r.Overlaps(o) {
+		return Range{}
+	}
+
+	return Range{Start: latest(r.Start, o.Start), End: earliest(r.End, o.End)}
+}
+
+// Union returns the smallest Range covering both r and o, provided they
+// overlap or are adjacent. Otherwise, the time between them would be
+// included so Union returns false. An empty Range is ignored.
+func (r Range) Union(o Range) (Range, bool) {
+	switch {
+	case r.IsEmpty():
+		return o, true
+	case o.IsEmpty():
+		return r, true
+	case r.Start.After(o.End) || o.Start.After(r.End):
+		return Range{}, false
+	}
+
+	return Range{Start: earliest(r.Start, o.Start), End: latest(r.End, o.End)}, true
+}
+
+// Subtract returns the (zero, one or two) non-empty parts of r that are not
+// within o, in chronological order.
+func (r Range) Subtract(o Range) []Range {
+	if r.IsEmpty() {
+		return nil
+	}
+
+	if !r.Overlaps(o) {
+		return []Range{r}
+	}
+
+	var out []Range
+
+	if r.Start.Before(o.Start) {
+		out = append(out, Range{Start: r.Start, End: o.Start})
+	}
+
+	if o.End.Before(r.End) {
+		out = append(out, Range{Start: o.End, End: r.End})
+	}
+
+	return out
+}
+
+// Split divides r into consecutive Ranges of length every, with the last
+// possibly being shorter. Split panics if every is not positive.
+func (r Range) Split(every time.Duration) []Range {
+	if every <= 0 {
+		panic("non-positive interval for Range.Split")
+	}
+
+	var out []Range
+
+	for start := r.Start; start.Before(r.End); start = start.Add(every) {
+		out = append(out, Range{Start: start, End: earliest(start.Add(every), r.End)})
+	}
+
+	return out
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("[%s, %s)", r.Start.Format(time.RFC3339Nano), r.End.Format(time.RFC3339Nano))
+}
+
+func earliest(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// RangeSet is a set of points in time, held as a normalized list of Ranges:
+// sorted, non-empty, and neither overlapping nor adjacent. The zero value is
+// an empty set ready for use.
+type RangeSet struct {
+	ranges []Range
+}
+
+// NewRangeSet returns a RangeSet holding the union of the given Ranges.
+func NewRangeSet(ranges ...Range) RangeSet {
+	var rs RangeSet
+	rs.Add(ranges...)
+	return rs
+}
+
+// Ranges returns the normalized Ranges of rs in chronological order.
+func (rs RangeSet) Ranges() []Range {
+	return append([]Range(nil), rs.ranges...)
+}
+
+// IsEmpty reports whether rs contains no time at all.
+func (rs RangeSet) IsEmpty() bool {
+	return len(rs.ranges) == 0
+}
+
+// Duration returns the total length of all of the Ranges in rs.
+func (rs RangeSet) Duration() time.Duration {
+	var d time.Duration
+	for _, r := range rs.ranges {
+		d += r.Duration()
+	}
+	return d
+}
+
+// Contains reports whether t falls within any of the Ranges in rs.
+func (rs RangeSet) Contains(t time.Time) bool {
+	i := sort.Search(len(rs.ranges), func(i int) bool { return t.Before(rs.ranges[i].End) })
+	return i < len(rs.ranges) && rs.ranges[i].Contains(t)
+}
+
+// Add adds the given Ranges to rs.
+func (rs *RangeSet) Add(ranges ...Range) {
+	all := make([]Range, 0, len(rs.ranges)+len(ranges))
+	all = append(all, rs.ranges...)
+
+	for _, r := range ranges {
+		if !r.IsEmpty() {
+			all = append(all, r)
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].Start.Before(all[j].Start) })
+
+	out := all[:0]
+	for _, r := range all {
+		if n := len(out); n > 0 {
+			if u, ok := out[n-1].Union(r); ok {
+				out[n-1] = u
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+
+	rs.ranges = out
+}
+
+// Remove removes the given Ranges from rs.
+func (rs *RangeSet) Remove(ranges ...Range) {
+	for _, o := range ranges {
+		var out []Range
+		for _, r := range rs.ranges {
+			out = append(out, r.Subtract(o)...)
+		}
+		rs.ranges = out
+	}
+}
+
+// Union returns a RangeSet holding all the time in either rs or o.
+func (rs RangeSet) Union(o RangeSet) RangeSet {
+	return NewRangeSet(append(rs.Ranges(), o.ranges...)...)
+}
+
+// Intersect returns a RangeSet holding the time common to both rs and o.
+func (rs RangeSet) Intersect(o RangeSet) RangeSet {
+	var out RangeSet
+
+	for i, j := 0, 0; i < len(rs.ranges) && j < len(o.ranges); {
+		a, b := rs.ranges[i], o.ranges[j]
+
+		if x := a.Intersect(b); !x.IsEmpty() {
+			out.ranges = append(out.ranges, x)
+		}
+
+		if a.End.Before(b.End) {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return out
+}
+
+// Subtract returns a RangeSet holding the time in rs that is not in o.
+func (rs RangeSet) Subtract(o RangeSet) RangeSet {
+	out := RangeSet{ranges: rs.Ranges()}
+	out.Remove(o.ranges...)
+	return out
+}
+
+func (rs RangeSet) String() string {
+	parts := make([]string, len(rs.ranges))
+	for i, r := range rs.ranges {
+		parts[i] = r.String()
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,108 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// hr returns the Range covering the given hours of 2024-01-01 (UTC).
+func hr(start, end int) Range {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return Range{Start: base.Add(time.Duration(start) * time.Hour), End: base.Add(time.Duration(end) * time.Hour)}
+}
+
+func TestRange(t *testing.T) {
+	r := hr(9, 17)
+
+	if !r.Contains(hr(9, 9).Start) || r.Contains(hr(17, 17).Start) {
+		t.Errorf("%v should contain its start but not its end", r)
+	}
+
+	if r.Overlaps(hr(17, 18)) || !r.Overlaps(hr(16, 18)) || r.Overlaps(hr(12, 12)) {
+		t.Errorf("Overlaps is wrong for %v", r)
+	}
+
+	if got, want := r.Intersect(hr(12, 20)), hr(12, 17); got != want {
+		t.Errorf("Intersect == %v; Wanted %v", got, want)
+	}
+
+	if got := r.Intersect(hr(18, 20)); got != (Range{}) {
+		t.Errorf("Intersect of disjoint ranges == %v; Wanted zero Range", got)
+	}
+
+	if got, ok := r.Union(hr(17, 20)); !ok || got != hr(9, 20) {
+		t.Errorf("Union of adjacent ranges == (%v, %t); Wanted %v", got, ok, hr(9, 20))
+	}
+
+	if _, ok := r.Union(hr(18, 20)); ok {
+		t.Errorf("Union of disjoint ranges should fail")
+	}
+
+	for _, tc := range []struct {
+		o    Range
+		want []Range
+	}{
+		{hr(12, 13), []Range{hr(9, 12), hr(13, 17)}},
+		{hr(8, 12), []Range{hr(12, 17)}},
+		{hr(12, 18), []Range{hr(9, 12)}},
+		{hr(8, 18), nil},
+		{hr(17, 18), []Range{r}},
+	} {
+		if got := r.Subtract(tc.o); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%v.Subtract(%v) == %v; Wanted %v", r, tc.o, got, tc.want)
+		}
+	}
+
+	if got, want := r.Split(3*time.Hour), []Range{hr(9, 12), hr(12, 15), hr(15, 17)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Split == %v; Wanted %v", got, want)
+	}
+
+	if d := hr(5, 2).Duration(); d != 0 {
+		t.Errorf("Duration of empty range == %v; Wanted 0", d)
+	}
+}
+
+func TestRangeSet(t *testing.T) {
+	rs := NewRangeSet(hr(13, 15), hr(1, 3), hr(2, 5), hr(5, 6), hr(8, 8), hr(10, 11))
+
+	if got, want := rs.Ranges(), []Range{hr(1, 6), hr(10, 11), hr(13, 15)}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("NewRangeSet == %v; Wanted %v", got, want)
+	}
+
+	if d := rs.Duration(); d != 8*time.Hour {
+		t.Errorf("Duration == %v; Wanted 8h", d)
+	}
+
+	for h, want := range map[int]bool{0: false, 1: true, 5: true, 6: false, 10: true, 12: false, 14: true, 15: false} {
+		if got := rs.Contains(hr(h, h).Start); got != want {
+			t.Errorf("Contains(%d:00) == %t; Wanted %t", h, got, want)
+		}
+	}
+
+	o := NewRangeSet(hr(4, 11), hr(14, 20))
+
+	if got, want := rs.Union(o).Ranges(), []Range{hr(1, 11), hr(13, 20)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Union == %v; Wanted %v", got, want)
+	}
+
+	if got, want := rs.Intersect(o).Ranges(), []Range{hr(4, 6), hr(10, 11), hr(14, 15)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Intersect == %v; Wanted %v", got, want)
+	}
+
+	if got, want := rs.Subtract(o).Ranges(), []Range{hr(1, 4), hr(13, 14)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Subtract == %v; Wanted %v", got, want)
+	}
+
+	// Subtract must not modify its receiver
+	if got, want := rs.Ranges(), []Range{hr(1, 6), hr(10, 11), hr(13, 15)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("After Subtract, receiver == %v; Wanted %v", got, want)
+	}
+
+	var empty RangeSet
+	if !empty.IsEmpty() || !empty.Intersect(rs).IsEmpty() {
+		t.Errorf("zero RangeSet should be empty")
+	}
+}
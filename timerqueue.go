@@ -0,0 +1,230 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// TimerID identifies a callback scheduled on a TimerQueue. The zero value
+// never identifies a scheduled callback.
+type TimerID uint64
+
+// TimerQueue runs callbacks at scheduled times. Unlike time.AfterFunc, which
+// creates a runtime timer for each callback, a TimerQueue keeps its pending
+// callbacks in a min-heap ordered by deadline and waits on a single timer
+// from a single goroutine, no matter how many callbacks are pending. This
+// makes it well suited to managing large numbers of waits, such as session
+// expirations.
+//
+// A TimerQueue is safe for concurrent use.
+type TimerQueue struct {
+	mu      sync.Mutex
+	entries timerHeap
+	byID    map[TimerID]*timerEntry
+	lastID  TimerID
+	wake    chan struct{}
+	done    chan struct{}
+	exited  chan struct{}
+	stop    sync.Once
+	calls   sync.WaitGroup
+}
+
+// NewTimerQueue returns a new TimerQueue along with the goroutine that runs
+// its callbacks. The Stop method should be called once the TimerQueue is no
+// longer needed.
+func NewTimerQueue() *TimerQueue {
+	tq := &TimerQueue{
+		byID:   make(map[TimerID]*timerEntry),
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+
+	go tq.run()
+
+	return tq
+}
+
+// Schedule arranges for fn to be called, in its own goroutine, once the
+// time at has been reached. The returned TimerID may be used to cancel or
+// reschedule the call.
+func (tq *TimerQueue) Schedule(at time.Time, fn func()) TimerID {
+	tq.mu.Lock()
+	defer tq.mu.Unlock()
+
+	tq.lastID++
+	e := &timerEntry{id: tq.lastID, at: at, fn: fn}
+
+	heap.Push(&tq.entries, e)
+	tq.byID[e.id] = e
+
+	if e.index == 0 {
+		tq.notify()
+	}
+
+	return e.id
+}
+
+// Cancel prevents the callback identified by id from being called. It
+// returns false if the callback has already been called (or started) or
+// has already been cancelled.
+func (tq *TimerQueue) Cancel(id TimerID) bool {
+	tq.mu.Lock()
+	defer tq.mu.Unlock()
+
+	e, ok := tq.byID[id]
+	if !ok {
+		return false
+	}
+
+	heap.Remove(&tq.entries, e.index)
+	delete(tq.byID, id)
+
+	return true
+}
+
+// Reschedule changes the time at which the callback identified by id will be
+// called. It returns false if the callback has already been called (or
+// started) or has been cancelled.
+func (tq *TimerQueue) Reschedule(id TimerID, at time.Time) bool {
+	tq.mu.Lock()
+	defer tq.mu.Unlock()
+
+	e, ok := tq.byID[id]
+	if !ok {
+		return false
+	}
+
+	e.at = at
+	heap.Fix(&tq.entries, e.index)
+
+	if e.index == 0 {
+		tq.notify()
+	}
+
+	return true
+}
+
+// Len returns the number of callbacks waiting to be called.
+func (tq *TimerQueue) Len() int {
+	tq.mu.Lock()
+	defer tq.mu.Unlock()
+	return len(tq.entries)
+}
+
+// Stop shuts down the TimerQueue. Callbacks not yet called will never be
+// called, and Stop waits for any that are still running to return; so, once
+// Stop returns, no callback is running or will run. Consequently, Stop must
+// not be called from within a callback. It is safe to call Stop more than
+// once.
+func (tq *TimerQueue) Stop() {
+	tq.stop.Do(func() { close(tq.done) })
+	<-tq.exited
+	tq.calls.Wait()
+}
+
+// notify wakes the run goroutine so that it will recalculate its wait. The
+// caller must hold tq.mu.
+func (tq *TimerQueue) notify() {
+	select {
+	case tq.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (tq *TimerQueue) run() {
+	defer close(tq.exited)
+
+	t := timeNewTimer(time.Hour)
+	stopAndFlush(t)
+
+	defer stopAndFlush(t)
+
+	for {
+		due, wait := tq.popDue()
+
+		tq.calls.Add(len(due))
+		for _, fn := range due {
+			go func(fn func()) {
+				defer tq.calls.Done()
+				fn()
+			}(fn)
+		}
+
+		var tc <-chan time.Time
+		if wait >= 0 {
+			stopAndFlush(t)
+			t.Reset(wait)
+			tc = t.Chan()
+		}
+
+		select {
+		case <-tq.done:
+			return
+		case <-tq.wake:
+		case <-tc:
+		}
+	}
+}
+
+// popDue removes and returns each callback whose time has been reached along
+// with how long to wait for the next one. If no callbacks remain, the
+// returned wait is negative.
+func (tq *TimerQueue) popDue() ([]func(), time.Duration) {
+	tq.mu.Lock()
+	defer tq.mu.Unlock()
+
+	var due []func()
+	now := timeNow()
+
+	for len(tq.entries) > 0 {
+		e := tq.entries[0]
+		if wait := e.at.Sub(now); wait > 0 {
+			return due, wait
+		}
+
+		heap.Pop(&tq.entries)
+		delete(tq.byID, e.id)
+		due = append(due, e.fn)
+	}
+
+	return due, -1
+}
+
+type timerEntry struct {
+	id    TimerID
+	at    time.Time
+	fn    func()
+	index int
+}
+
+// timerHeap implements heap.Interface for a min-heap of timerEntry values
+// ordered by deadline.
+type timerHeap []*timerEntry
+
+func (th timerHeap) Len() int           { return len(th) }
+func (th timerHeap) Less(i, j int) bool { return th[i].at.Before(th[j].at) }
+
+func (th timerHeap) Swap(i, j int) {
+	th[i], th[j] = th[j], th[i]
+	th[i].index = i
+	th[j].index = j
+}
+
+func (th *timerHeap) Push(x any) {
+	e := x.(*timerEntry)
+	e.index = len(*th)
+	*th = append(*th, e)
+}
+
+func (th *timerHeap) Pop() any {
+	old := *th
+	n := len(old) - 1
+	e := old[n]
+	old[n] = nil
+	*th = old[:n]
+	return e
+}
@@ -0,0 +1,204 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTimerQueue(t *testing.T) {
+	defer resetTimeFuncs()
+
+	var (
+		mu    sync.Mutex
+		clock = now
+	)
+
+	timeNow = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return clock
+	}
+
+	// The queue's timer "fires" whenever the test sends on fired; the queue
+	// then calls whatever has come due according to clock.
+	fired := make(chan time.Time, 1)
+	timeNewTimer = func(time.Duration) timer {
+		return &fakeTimer{c: fired}
+	}
+
+	tq := NewTimerQueue()
+	defer tq.Stop()
+
+	at := func(n int) time.Time {
+		return now.Add(time.Duration(n) * 10 * time.Millisecond)
+	}
+
+	called := make(chan int)
+	record := func(n int) func() {
+		return func() {
+			if got := timeNow(); got.Before(at(n)) {
+				t.Errorf("callback %d called at %v; Wanted no earlier than %v", n, got, at(n))
+			}
+			called <- n
+		}
+	}
+
+	tq.Schedule(at(3), record(3))
+	tq.Schedule(at(1), record(1))
+	moved := tq.Schedule(at(2), record(4))
+	cancelled := tq.Schedule(at(2), func() { t.Error("cancelled callback was called") })
+
+	if !tq.Reschedule(moved, at(4)) {
+		t.Error("Reschedule() == false; Wanted true")
+	}
+
+	if !tq.Cancel(cancelled) {
+		t.Error("Cancel() == false; Wanted true")
+	}
+
+	if tq.Cancel(cancelled) {
+		t.Error("second Cancel() == true; Wanted false")
+	}
+
+	if got := tq.Len(); got != 3 {
+		t.Errorf("Len() == %d; Wanted 3", got)
+	}
+
+	// Nothing is due at step 2, since its callback was moved and the other
+	// was cancelled.
+	steps := []struct {
+		step int
+		want int
+	}{{1, 1}, {2, 0}, {3, 3}, {4, 4}}
+
+	for _, s := range steps {
+		step, want := s.step, s.want
+
+		mu.Lock()
+		clock = at(step)
+		mu.Unlock()
+
+		fired <- clock
+
+		if want == 0 {
+			continue
+		}
+
+		select {
+		case got := <-called:
+			if got != want {
+				t.Errorf("at step %d, callback %d was called; Wanted %d", step, got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("at step %d, timed out waiting for callback %d", step, want)
+		}
+	}
+
+	if got := tq.Len(); got != 0 {
+		t.Errorf("Len() == %d; Wanted 0", got)
+	}
+
+	if tq.Reschedule(moved, at(5)) {
+		t.Error("Reschedule() of a called callback == true; Wanted false")
+	}
+}
+
+func TestTimerQueueStop(t *testing.T) {
+	tq := NewTimerQueue()
+
+	var (
+		started  = make(chan struct{})
+		release  = make(chan struct{})
+		finished atomic.Bool
+	)
+
+	tq.Schedule(time.Now(), func() {
+		close(started)
+		<-release
+		finished.Store(true)
+	})
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for callback")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		tq.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned while a callback was running")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	<-stopped
+
+	if !finished.Load() {
+		t.Error("Stop returned before the running callback finished")
+	}
+
+	tq.Stop() // A second Stop is harmless.
+}
+
+func BenchmarkTimerQueue(b *testing.B) {
+	tq := NewTimerQueue()
+	defer tq.Stop()
+
+	at := time.Now().Add(time.Hour)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		tq.Cancel(tq.Schedule(at, func() {}))
+	}
+}
+
+// BenchmarkAfterFunc measures the same workload as BenchmarkTimerQueue using
+// time.AfterFunc, for comparison.
+func BenchmarkAfterFunc(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		time.AfterFunc(time.Hour, func() {}).Stop()
+	}
+}
+
+func BenchmarkTimerQueuePending(b *testing.B) {
+	tq := NewTimerQueue()
+	defer tq.Stop()
+
+	at := time.Now().Add(time.Hour)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		tq.Schedule(at.Add(time.Duration(i)), func() {})
+	}
+}
+
+func BenchmarkAfterFuncPending(b *testing.B) {
+	timers := make([]*time.Timer, 0, b.N)
+	defer func() {
+		for _, t := range timers {
+			t.Stop()
+		}
+	}()
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		timers = append(timers, time.AfterFunc(time.Hour+time.Duration(i), func() {}))
+	}
+}
@@ -20,7 +20,11 @@
 
 package timetool
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 // FromMillis interprets millis as milliseconds since the Epoch and returns
 // the equivalent time.Time value.
@@ -28,10 +32,7 @@ import "time"
 // Deprecated: As of Go v1.17, the standard library's time package provides
 // the function UnixMilli that exhibits the same behavior.
 func FromMillis(millis int64) time.Time {
-	sse := millis / 1000
-	mso := int64(time.Millisecond) * (millis - (sse * 1000))
-
-	return time.Unix(sse, mso)
+	return FromEpoch(millis, EpochMillis)
 }
 
 // ToMillis returns the number of milliseconds since the Epoch for the provided
@@ -43,3 +44,145 @@ func ToMillis(t time.Time) int64 {
 	t = t.Round(time.Millisecond)
 	return t.UnixNano() / int64(time.Millisecond)
 }
+
+// EpochUnit is the unit of a numeric timestamp measured from the Unix Epoch.
+type EpochUnit int
+
+const (
+	// EpochSeconds is for timestamps counted in seconds, as with
+	// time.Time's Unix method and a Unix time_t.
+	EpochSeconds EpochUnit = iota
+
+	// EpochMillis is for timestamps counted in milliseconds, as commonly
+	// used by Java and JavaScript.
+	EpochMillis
+
+	// EpochMicros is for timestamps counted in microseconds.
+	EpochMicros
+
+	// EpochNanos is for timestamps counted in nanoseconds, as with
+	// time.Time's UnixNano method.
+	EpochNanos
+)
+
+func (u EpochUnit) String() string {
+	switch u {
+	case EpochSeconds:
+		return "seconds"
+	case EpochMillis:
+		return "milliseconds"
+	case EpochMicros:
+		return "microseconds"
+	case EpochNanos:
+		return "nanoseconds"
+	default:
+		return fmt.Sprintf("EpochUnit(%d)", int(u))
+	}
+}
+
+// perSecond returns the number of u's in a second.
+func (u EpochUnit) perSecond() int64 {
+	switch u {
+	case EpochMillis:
+		return 1e3
+	case EpochMicros:
+		return 1e6
+	case EpochNanos:
+		return 1e9
+	default:
+		return 1
+	}
+}
+
+// EpochValue is the set of numeric types accepted by FromEpoch and
+// DetectEpochUnit.
+type EpochValue interface {
+	int64 | float64
+}
+
+// FromEpoch interprets v as a number of the given units since the Epoch and
+// returns the equivalent time.Time value. A float64 value may carry a
+// fractional part (e.g. 1279156356.512 seconds), which is rounded to the
+// nearest nanosecond. Negative values (i.e. those before 1970) are handled
+// such that any fractional second is always counted forward from the
+// preceding whole second.
+//
+// A float64 value that is NaN, infinite, or outside the range of an int64
+// does not describe a usable timestamp; for these, the zero Time is returned
+// (which DetectEpochUnit, in turn, never reports as plausible).
+//
+// An unknown unit is treated as EpochSeconds.
+func FromEpoch[T EpochValue](v T, unit EpochUnit) time.Time {
+	per := unit.perSecond()
+
+	switch x := any(v).(type) {
+	case int64:
+		sec := x / per
+		rem := x % per
+
+		if rem < 0 {
+			sec--
+			rem += per
+		}
+
+		return time.Unix(sec, rem*(1e9/per))
+
+	case float64:
+		// NaN fails both comparisons, and so is caught by their negation.
+		if !(x >= math.MinInt64 && x < math.MaxInt64) {
+			return time.Time{}
+		}
+
+		whole := math.Floor(x)
+		frac := math.Round((x - whole) * float64(1e9/per))
+		return FromEpoch(int64(whole), unit).Add(time.Duration(frac))
+	}
+
+	panic("unreachable")
+}
+
+// ToEpoch returns the number of the given units since the Epoch for the
+// provided time.Time value t, truncated toward the beginning of time. An
+// unknown unit is treated as EpochSeconds.
+//
+// A time too far from the Epoch to be counted in an int64 yields
+// math.MinInt64 or math.MaxInt64 instead. For EpochNanos, this limits the
+// valid range to between 1677-09-21 00:12:43.145224192 and
+// 2262-04-11 23:47:16.854775807 UTC; the other units span far more time.
+func ToEpoch(t time.Time, unit EpochUnit) int64 {
+	per := unit.perSecond()
+	sec, frac := t.Unix(), int64(t.Nanosecond())/(1e9/per)
+
+	// The result is sec*per + frac, provided that lies within an int64. Any
+	// intermediate overflow (near the lower limit) cancels out.
+	switch {
+	case sec >= 0 && sec > (math.MaxInt64-frac)/per:
+		return math.MaxInt64
+	case sec < 0 && sec+1 < (math.MinInt64+per-frac)/per:
+		return math.MinInt64
+	}
+
+	return sec*per + frac
+}
+
+var (
+	epochWindowStart = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+	epochWindowEnd   = time.Date(2200, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// DetectEpochUnit guesses the unit of the Epoch based timestamp v by its
+// magnitude. It returns the coarsest unit for which v represents a time from
+// 1900 up to (but not including) 2200. If v is not plausible in any unit,
+// false is returned.
+//
+// Note that small values are inherently ambiguous; for example, a value of
+// 1000 is taken as 1000 seconds (and not 1 second expressed in milliseconds).
+func DetectEpochUnit[T EpochValue](v T) (EpochUnit, bool) {
+	for u := EpochSeconds; u <= EpochNanos; u++ {
+		if t := FromEpoch(v, u); !t.Before(epochWindowStart) && t.Before(epochWindowEnd) {
+			return u, true
+		}
+	}
+
+	return 0, false
+}
@@ -21,6 +21,7 @@
 package timetool
 
 import (
+	"math"
 	"testing"
 	"time"
 )
@@ -43,3 +44,91 @@ func TestFromMillis(t *testing.T) {
 		t.Errorf("FromMillis(%d) == %v; Wanted: %v", millisValue, got, want)
 	}
 }
+
+func TestFromEpoch(t *testing.T) {
+	base := time.Date(2010, 7, 15, 1, 12, 36, 0, time.UTC)
+	pre := time.Date(1969, 12, 31, 23, 59, 58, int(500*time.Millisecond), time.UTC)
+
+	cases := []struct {
+		name string
+		got  time.Time
+		want time.Time
+	}{
+		{"seconds", FromEpoch(int64(1279156356), EpochSeconds), base},
+		{"millis", FromEpoch(int64(1279156356512), EpochMillis), base.Add(512 * time.Millisecond)},
+		{"micros", FromEpoch(int64(1279156356512345), EpochMicros), base.Add(512345 * time.Microsecond)},
+		{"nanos", FromEpoch(int64(1279156356512345678), EpochNanos), base.Add(512345678)},
+		{"float-seconds", FromEpoch(1279156356.5, EpochSeconds), base.Add(500 * time.Millisecond)},
+		{"float-millis", FromEpoch(1279156356512.0, EpochMillis), base.Add(512 * time.Millisecond)},
+		{"negative-millis", FromEpoch(int64(-1500), EpochMillis), pre},
+		{"negative-nanos", FromEpoch(int64(-1500000000), EpochNanos), pre},
+		{"negative-float", FromEpoch(-1.5, EpochSeconds), pre},
+		{"FromMillis", FromMillis(-1500), pre},
+	}
+
+	for _, tc := range cases {
+		if !tc.got.Equal(tc.want) {
+			t.Errorf("%s: got %v; wanted %v", tc.name, tc.got.UTC(), tc.want)
+		}
+	}
+
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1), 1e19, -1e19} {
+		if got := FromEpoch(v, EpochNanos); !got.IsZero() {
+			t.Errorf("FromEpoch(%v, EpochNanos) == %v; Wanted the zero Time", v, got)
+		}
+
+		if u, ok := DetectEpochUnit(v); ok {
+			t.Errorf("DetectEpochUnit(%v) == (%v, true); Wanted false", v, u)
+		}
+	}
+
+	for _, tc := range []struct {
+		t    time.Time
+		unit EpochUnit
+		want int64
+	}{
+		{time.Unix(0, math.MaxInt64), EpochNanos, math.MaxInt64},
+		{time.Unix(0, math.MinInt64), EpochNanos, math.MinInt64},
+		{time.Unix(0, math.MinInt64+999), EpochMicros, math.MinInt64 / 1000},
+		{time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC), EpochNanos, math.MaxInt64},
+		{time.Date(1600, 1, 1, 0, 0, 0, 0, time.UTC), EpochNanos, math.MinInt64},
+		{time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC), EpochMillis, 10413792000000},
+	} {
+		if got := ToEpoch(tc.t, tc.unit); got != tc.want {
+			t.Errorf("ToEpoch(%v, %v) == %d; Wanted %d", tc.t.UTC(), tc.unit, got, tc.want)
+		}
+	}
+
+	if got := ToEpoch(pre, EpochMillis); got != -1500 {
+		t.Errorf("ToEpoch(%v, EpochMillis) == %d; Wanted -1500", pre, got)
+	}
+
+	if got := ToEpoch(base.Add(512345678), EpochMicros); got != 1279156356512345 {
+		t.Errorf("ToEpoch(%v, EpochMicros) == %d; Wanted 1279156356512345", base, got)
+	}
+}
+
+func TestDetectEpochUnit(t *testing.T) {
+	cases := []struct {
+		v    int64
+		want EpochUnit
+		ok   bool
+	}{
+		{1279156356, EpochSeconds, true},
+		{-1279156356, EpochSeconds, true},
+		{1279156356512, EpochMillis, true},
+		{1279156356512345, EpochMicros, true},
+		{1279156356512345678, EpochNanos, true},
+		{-5000000000000000000, 0, false},
+	}
+
+	for _, tc := range cases {
+		if got, ok := DetectEpochUnit(tc.v); got != tc.want || ok != tc.ok {
+			t.Errorf("DetectEpochUnit(%d) == (%v, %t); Wanted (%v, %t)", tc.v, got, ok, tc.want, tc.ok)
+		}
+	}
+
+	if got, ok := DetectEpochUnit(1279156356512.5); got != EpochMillis || !ok {
+		t.Errorf("DetectEpochUnit(1279156356512.5) == (%v, %t); Wanted (%v, true)", got, ok, EpochMillis)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"sync"
+	"time"
+)
+
+// FixedWindowLimiter allows up to limit events per key within each
+// consecutive window of time. Windows are aligned to multiples of their
+// length since the zero Time (so a one minute window begins at the top of
+// each minute). Since the count for a key resets at each boundary, up to
+// twice limit events may occur within a span of window that straddles one.
+//
+// Keys that have been idle for a full window are evicted to bound memory.
+//
+// A FixedWindowLimiter is safe for concurrent use.
+type FixedWindowLimiter struct {
+	windowLimiter
+}
+
+// NewFixedWindowLimiter returns a new FixedWindowLimiter allowing limit
+// events per window for each key. A limit less than 1 is treated as 1. If
+// window is not positive, all events are allowed.
+func NewFixedWindowLimiter(limit int, window time.Duration) *FixedWindowLimiter {
+	return &FixedWindowLimiter{newWindowLimiter(limit, window, func() windowState { return &fixedWindow{} })}
+}
+
+// SlidingWindowMode selects how a SlidingWindowLimiter counts events.
+type SlidingWindowMode int
+
+const (
+	// SlidingApprox estimates the number of events in the trailing window
+	// from the counts for the current and previous fixed windows, assuming
+	// events in the previous window were evenly spaced. It uses a constant
+	// amount of memory per key.
+	SlidingApprox SlidingWindowMode = iota
+
+	// SlidingExact records the time of each allowed event, which makes it
+	// exact at the cost of memory proportional to limit for each key.
+	SlidingExact
+)
+
+// SlidingWindowLimiter allows no more than limit events per key within any
+// trailing span of window.
+//
+// Keys that have been idle long enough that they would have no effect on
+// future events are evicted to bound memory.
+//
+// A SlidingWindowLimiter is safe for concurrent use.
+type SlidingWindowLimiter struct {
+	windowLimiter
+}
+
+// NewSlidingWindowLimiter returns a new SlidingWindowLimiter, using the given
+// mode, that allows limit events in any window for each key. A limit less
+// than 1 is treated as 1. If window is not positive, all events are allowed.
+func NewSlidingWindowLimiter(limit int, window time.Duration, mode SlidingWindowMode) *SlidingWindowLimiter {
+	newState := func() windowState { return &approxWindow{} }
+	if mode == SlidingExact {
+		newState = func() windowState { return &exactWindow{} }
+	}
+
+	return &SlidingWindowLimiter{newWindowLimiter(limit, window, newState)}
+}
+
+// windowState tracks the recent events for a single key.
+type windowState interface {
+	// allow reports whether an event may happen at now, recording it if so.
+	allow(now time.Time, limit int, window time.Duration) bool
+
+	// idle reports whether the state may be discarded without affecting
+	// future calls to allow.
+	idle(now time.Time, window time.Duration) bool
+}
+
+// windowLimiter holds the machinery shared by each of this package's window
+// based limiters.
+type windowLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	window   time.Duration
+	newState func() windowState
+	keys     map[string]windowState
+	swept    time.Time
+}
+
+func newWindowLimiter(limit int, window time.Duration, newState func() windowState) windowLimiter {
+	if limit < 1 {
+		limit = 1
+	}
+
+	return windowLimiter{
+		limit:    limit,
+		window:   window,
+		newState: newState,
+		keys:     make(map[string]windowState),
+	}
+}
+
+// Allow reports whether an event for key may happen now, recording the event
+// if so.
+func (wl *windowLimiter) Allow(key string) bool {
+	if wl.window <= 0 {
+		return true
+	}
+
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+
+	now := timeNow()
+	wl.sweep(now)
+
+	ws, ok := wl.keys[key]
+	if !ok {
+		ws = wl.newState()
+		wl.keys[key] = ws
+	}
+
+	return ws.allow(now, wl.limit, wl.window)
+}
+
+// Len returns the number of keys currently being tracked.
+func (wl *windowLimiter) Len() int {
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+
+	return len(wl.keys)
+}
+
+// sweep evicts idle keys, at most once per window. Must be called with wl.mu
+// held.
+func (wl *windowLimiter) sweep(now time.Time) {
+	if now.Sub(wl.swept) < wl.window {
+		return
+	}
+
+	for k, ws := range wl.keys {
+		if ws.idle(now, wl.window) {
+			delete(wl.keys, k)
+		}
+	}
+
+	wl.swept = now
+}
+
+type fixedWindow struct {
+	start time.Time
+	count int
+}
+
+func (fw *fixedWindow) allow(now time.Time, limit int, window time.Duration) bool {
+	if start := now.Truncate(window); !start.Equal(fw.start) {
+		fw.start, fw.count = start, 0
+	}
+
+	if fw.count >= limit {
+		return false
+	}
+
+	fw.count++
+	return true
+}
+
+func (fw *fixedWindow) idle(now time.Time, window time.Duration) bool {
+	return !now.Before(fw.start.Add(window))
+}
+
+type approxWindow struct {
+	start time.Time
+	prev  int
+	cur   int
+}
+
+func (aw *approxWindow) allow(now time.Time, limit int, window time.Duration) bool {
+	switch start := now.Truncate(window); {
+	case start.Equal(aw.start):
+	case start.Equal(aw.start.Add(window)):
+		aw.start, aw.prev, aw.cur = start, aw.cur, 0
+	default:
+		aw.start, aw.prev, aw.cur = start, 0, 0
+	}
+
+	// The portion of the previous window still within the trailing window.
+	weight := 1 - float64(now.Sub(aw.start))/float64(window)
+
+	if float64(aw.prev)*weight+float64(aw.cur) >= float64(limit) {
+		return false
+	}
+
+	aw.cur++
+	return true
+}
+
+func (aw *approxWindow) idle(now time.Time, window time.Duration) bool {
+	return !now.Before(aw.start.Add(2 * window))
+}
+
+type exactWindow struct {
+	events []time.Time
+}
+
+func (ew *exactWindow) allow(now time.Time, limit int, window time.Duration) bool {
+	cutoff := now.Add(-window)
+
+	i := 0
+	for i < len(ew.events) && !ew.events[i].After(cutoff) {
+		i++
+	}
+
+	ew.events = ew.events[i:]
+
+	if len(ew.events) >= limit {
+		return false
+	}
+
+	ew.events = append(ew.events, now)
+	return true
+}
+
+func (ew *exactWindow) idle(now time.Time, window time.Duration) bool {
+	return len(ew.events) == 0 || !ew.events[len(ew.events)-1].After(now.Add(-window))
+}
@@ -0,0 +1,137 @@
+// Copyright © 2024 Timothy E. Peoples
+
+package timetool
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFixedWindowLimiter(t *testing.T) {
+	defer resetTimeFuncs()
+
+	clock := now.Truncate(time.Minute)
+	timeNow = func() time.Time { return clock }
+
+	wl := NewFixedWindowLimiter(2, time.Minute)
+
+	check := func(key string, want bool) {
+		t.Helper()
+		if got := wl.Allow(key); got != want {
+			t.Errorf("Allow(%q) at %v == %t; Wanted %t", key, clock.Format(time.TimeOnly), got, want)
+		}
+	}
+
+	check("a", true)
+	check("a", true)
+	check("a", false)
+	check("b", true)
+
+	// The count resets at the window boundary
+	clock = clock.Add(59 * time.Second)
+	check("a", false)
+	clock = clock.Add(time.Second)
+	check("a", true)
+	check("a", true)
+	check("a", false)
+
+	clock = clock.Add(2 * time.Minute)
+	check("c", true)
+
+	if n := wl.Len(); n != 1 {
+		t.Errorf("Len() after idle period == %d; Wanted 1", n)
+	}
+}
+
+func TestSlidingWindowLimiter(t *testing.T) {
+	defer resetTimeFuncs()
+
+	start := now.Truncate(time.Minute)
+	clock := start
+	timeNow = func() time.Time { return clock }
+
+	// The approximation assumes the previous window's events were spread
+	// evenly across it so, since they actually came late, it allows events
+	// slightly sooner than it should.
+	for _, tc := range []struct {
+		mode        SlidingWindowMode
+		afterBorder int
+		afterWindow int
+	}{
+		{SlidingApprox, 1, 3},
+		{SlidingExact, 0, 4},
+	} {
+		mode := tc.mode
+		clock = start
+		wl := NewSlidingWindowLimiter(4, time.Minute, mode)
+
+		allowed := func(n int, key string) int {
+			got := 0
+			for i := 0; i < n; i++ {
+				if wl.Allow(key) {
+					got++
+				}
+			}
+			return got
+		}
+
+		// 4 events late in the first window
+		clock = start.Add(45 * time.Second)
+		if got := allowed(6, "k"); got != 4 {
+			t.Errorf("mode %d: allowed %d events; Wanted 4", mode, got)
+		}
+
+		// A fixed window would reset here, but all 4 events are still
+		// within the trailing minute.
+		clock = start.Add(61 * time.Second)
+		if got := allowed(2, "k"); got != tc.afterBorder {
+			t.Errorf("mode %d: allowed %d events just after boundary; Wanted %d", mode, got, tc.afterBorder)
+		}
+
+		// Once the earlier events have left the window, new ones are allowed.
+		clock = start.Add(106 * time.Second)
+		if got := allowed(6, "k"); got != tc.afterWindow {
+			t.Errorf("mode %d: allowed %d events after window passed; Wanted %d", mode, got, tc.afterWindow)
+		}
+
+		if got := allowed(1, "other"); got != 1 {
+			t.Errorf("mode %d: allowed %d events for a different key; Wanted 1", mode, got)
+		}
+
+		clock = start.Add(10 * time.Minute)
+		if got := allowed(1, "new"); got != 1 {
+			t.Errorf("mode %d: allowed %d events for a new key; Wanted 1", mode, got)
+		}
+
+		if n := wl.Len(); n != 1 {
+			t.Errorf("mode %d: Len() after idle period == %d; Wanted 1", mode, n)
+		}
+	}
+}
+
+func TestSlidingWindowExact(t *testing.T) {
+	defer resetTimeFuncs()
+
+	clock := now
+	timeNow = func() time.Time { return clock }
+
+	wl := NewSlidingWindowLimiter(3, 10*time.Second, SlidingExact)
+
+	for _, tc := range []struct {
+		at   time.Duration
+		want bool
+	}{
+		{0, true},
+		{4 * time.Second, true},
+		{8 * time.Second, true},
+		{9 * time.Second, false},
+		{10 * time.Second, true}, // the event at 0s has left the window
+		{13 * time.Second, false},
+		{14 * time.Second, true},
+	} {
+		clock = now.Add(tc.at)
+		if got := wl.Allow("k"); got != tc.want {
+			t.Errorf("Allow() at +%v == %t; Wanted %t", tc.at, got, tc.want)
+		}
+	}
+}